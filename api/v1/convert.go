@@ -3,7 +3,7 @@
 // SPDX-License-Identifier: Apache-2.0
 // Keep your secrets... secret.
 
-package data
+package v1
 
 import (
 	"bytes"
@@ -0,0 +1,23 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+// Package v1 contains the types that VSecM Safe exchanges with its clients
+// over the wire, as of version 1 of the VSecM Safe API.
+//
+// The SDK returns these types from its public functions, so downstream code
+// can name them; for example, to write helpers that accept a
+// SecretFetchResponse, or fakes that return one:
+//
+//	import api "github.com/spiffe/vsecm-sdk-go/api/v1"
+//
+//	func fakeFetch() (api.SecretFetchResponse, error) {
+//	    return api.SecretFetchResponse{Data: "{}"}, nil
+//	}
+//
+// Compatibility: within v1, exported types, fields, and their JSON names
+// are neither removed nor renamed, and the meaning of existing fields does
+// not change. New fields may be added. Breaking changes go to a new
+// package (e.g. api/v2).
+package v1
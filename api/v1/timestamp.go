@@ -0,0 +1,65 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the layouts that VSecM Safe is known to emit
+// timestamps in. The last two are the layouts of time.Time's String method;
+// it prints the offset in place of the zone name if the zone has none.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999 -0700 -0700",
+}
+
+// parseTimestamp parses a timestamp that VSecM Safe has sent as a string.
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, errors.New("empty timestamp")
+	}
+
+	// time.Time's String method appends the monotonic clock reading
+	// (e.g. " m=+0.000000001"), which cannot be parsed back.
+	if i := strings.Index(s, " m="); i != -1 {
+		s = s[:i]
+	}
+
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse timestamp %q", s)
+}
+
+// CreatedTime returns the Created field of the response as a time.Time.
+// It returns an error if Created is empty or is not in a known format.
+func (r SecretFetchResponse) CreatedTime() (time.Time, error) {
+	return parseTimestamp(r.Created)
+}
+
+// UpdatedTime returns the Updated field of the response as a time.Time.
+// It returns an error if Updated is empty or is not in a known format.
+//
+// UpdatedTime is handy to tell whether a secret has changed since the last
+// time it has been fetched:
+//
+//	updated, err := r.UpdatedTime()
+//	if err == nil && updated.After(lastSeen) {
+//	    reload()
+//	}
+func (r SecretFetchResponse) UpdatedTime() (time.Time, error) {
+	return parseTimestamp(r.Updated)
+}
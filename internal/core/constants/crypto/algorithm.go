@@ -5,4 +5,8 @@
 
 package crypto
 
-type Algorithm string
+import (
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+)
+
+type Algorithm = api.Algorithm
@@ -13,13 +13,31 @@ type VarName string
 
 const SpiffeEndpointSocket VarName = "SPIFFE_ENDPOINT_SOCKET"
 const SpiffeTrustDomain VarName = "SPIFFE_TRUST_DOMAIN"
+const VSecMInitContainerHealthBindAddr VarName = "VSECM_INIT_CONTAINER_HEALTH_BIND_ADDR"
+const VSecMInitContainerFailureExitCode VarName = "VSECM_INIT_CONTAINER_FAILURE_EXIT_CODE"
+const VSecMInitContainerPersistSecret VarName = "VSECM_INIT_CONTAINER_PERSIST_SECRET"
 const VSecMInitContainerPollInterval VarName = "VSECM_INIT_CONTAINER_POLL_INTERVAL"
+const VSecMInitContainerTimeout VarName = "VSECM_INIT_CONTAINER_TIMEOUT"
 const VSecMLogLevel VarName = "VSECM_LOG_LEVEL"
 const VSecMSafeEndpointUrl VarName = "VSECM_SAFE_ENDPOINT_URL"
 const VSecMSidecarPollInterval VarName = "VSECM_SIDECAR_POLL_INTERVAL"
 const VSecMSidecarSecretsPath VarName = "VSECM_SIDECAR_SECRETS_PATH"
+const VSecMSidecarOutputs VarName = "VSECM_SIDECAR_OUTPUTS"
+const VSecMSidecarOutputsFile VarName = "VSECM_SIDECAR_OUTPUTS_FILE"
+const VSecMSidecarHooks VarName = "VSECM_SIDECAR_HOOKS"
+const VSecMSidecarHooksFile VarName = "VSECM_SIDECAR_HOOKS_FILE"
+const VSecMSidecarDeletePolicy VarName = "VSECM_SIDECAR_DELETE_POLICY"
+const VSecMSidecarDeleteGracePeriod VarName = "VSECM_SIDECAR_DELETE_GRACE_PERIOD"
+const VSecMSidecarTombstonePath VarName = "VSECM_SIDECAR_TOMBSTONE_PATH"
+const VSecMSidecarHealthBindAddr VarName = "VSECM_SIDECAR_HEALTH_BIND_ADDR"
+const VSecMSidecarStalePolls VarName = "VSECM_SIDECAR_STALE_POLLS"
+const VSecMSidecarSecretsFileMode VarName = "VSECM_SIDECAR_SECRETS_FILE_MODE"
+const VSecMSidecarSecretsFileUid VarName = "VSECM_SIDECAR_SECRETS_FILE_UID"
+const VSecMSidecarSecretsFileGid VarName = "VSECM_SIDECAR_SECRETS_FILE_GID"
 const VSecMSpiffeIdPrefixSafe VarName = "VSECM_SPIFFEID_PREFIX_SAFE"
-const VSecMSpiffeIdPrefixClerk VarName = "VSECM_SPIFFEID_PREFIX_SAFE"
+const VSecMSpiffeIdPrefixClerk VarName = "VSECM_SPIFFEID_PREFIX_CLERK"
+const VSecMSpiffeIdPrefixScout VarName = "VSECM_SPIFFEID_PREFIX_SCOUT"
+const VSecMSpiffeIdPrefixSentinel VarName = "VSECM_SPIFFEID_PREFIX_SENTINEL"
 const VSecMSpiffeIdPrefixWorkload VarName = "VSECM_SPIFFEID_PREFIX_WORKLOAD"
 const VSecMWorkloadNameRegExp VarName = "VSECM_WORKLOAD_NAME_REGEXP"
 
@@ -27,12 +45,16 @@ type VarValue string
 
 const SpiffeEndpointSocketDefault VarValue = "unix:///spire-agent-socket/spire-agent.sock"
 const SpiffeTrustDomainDefault VarValue = "vsecm.com"
+const VSecMInitContainerFailureExitCodeDefault VarValue = "1"
 const VSecMInitContainerPollIntervalDefault VarValue = "5000"
 const VSecMSafeEndpointUrlDefault VarValue = "https://vsecm-safe.vsecm-system.svc.cluster.local:8443/"
 const VSecMSidecarPollIntervalDefault VarValue = "20000"
 const VSecMSidecarSecretsPathDefault VarValue = "/opt/vsecm/secrets.json"
+const VSecMSidecarSecretsFileModeDefault VarValue = "0600"
 const VSecMSpiffeIdPrefixSafeDefault VarValue = "^spiffe://vsecm.com/workload/vsecm-safe/ns/vsecm-system/sa/vsecm-safe/n/[^/]+$"
-const VSecMSpiffeIdPrefixClerkDefault VarValue = "^spiffe://vsecm.com/workload/vsecm-clerk/ns/vsecm-clerk/sa/vsecm-safe/n/[^/]+$"
+const VSecMSpiffeIdPrefixClerkDefault VarValue = "^spiffe://vsecm.com/workload/vsecm-clerk/ns/vsecm-clerk/sa/vsecm-clerk/n/[^/]+$"
+const VSecMSpiffeIdPrefixScoutDefault VarValue = "^spiffe://vsecm.com/workload/vsecm-scout/ns/vsecm-system/sa/vsecm-scout/n/[^/]+$"
+const VSecMSpiffeIdPrefixSentinelDefault VarValue = "^spiffe://vsecm.com/workload/vsecm-sentinel/ns/vsecm-system/sa/vsecm-sentinel/n/[^/]+$"
 const VSecMSpiffeIdPrefixWorkloadDefault VarValue = "^spiffe://vsecm.com/workload/[^/]+/ns/[^/]+/sa/[^/]+/n/[^/]+$"
 const VSecMNameRegExpForWorkloadDefault VarValue = "^spiffe://vsecm.com/workload/([^/]+)/ns/[^/]+/sa/[^/]+/n/[^/]+$"
 
@@ -6,52 +6,29 @@
 package data
 
 import (
-	"github.com/spiffe/vsecm-sdk-go/internal/lib/entity"
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
 )
 
+// The secret types are defined in the public api/v1 package.
+// These aliases keep the existing internal code compiling.
+
 // SecretFormat represents the format of the secret.
-type SecretFormat string
+type SecretFormat = api.SecretFormat
 
-var (
-	Json SecretFormat = "json"
-	Yaml SecretFormat = "yaml"
-	Raw  SecretFormat = "raw"
+const (
+	Json = api.Json
+	Yaml = api.Yaml
+	Raw  = api.Raw
 )
 
 // Secret represents the secret that is safe to view.
-type Secret struct {
-	Name         string          `json:"name"`
-	Created      entity.JsonTime `json:"created"`
-	Updated      entity.JsonTime `json:"updated"`
-	NotBefore    entity.JsonTime `json:"notBefore"`
-	ExpiresAfter entity.JsonTime `json:"expiresAfter"`
-}
+type Secret = api.Secret
 
 // SecretEncrypted represents the secret with an encrypted value.
-// It is still safe to view since the value of it is encrypted.
-type SecretEncrypted struct {
-	Name           string          `json:"name"`
-	EncryptedValue string          `json:"value"`
-	Created        entity.JsonTime `json:"created"`
-	Updated        entity.JsonTime `json:"updated"`
-	NotBefore      entity.JsonTime `json:"notBefore"`
-	ExpiresAfter   entity.JsonTime `json:"expiresAfter"`
-}
-
-// SecretMeta represents the metadata of the secret that is not
-// directly relevant to the secret itself but provides additional
-// context for VSecM Safe's internal operations.
-type SecretMeta struct {
-	// Defaults to "default"
-	Namespaces []string `json:"namespaces"`
-	// Go template used to transform the secret.
-	// Sample secret:
-	// '{"username":"admin","password":"VSecMRocks"}'
-	// Sample template:
-	// '{"USER":"{{.username}}", "PASS":"{{.password}}"}"
-	Template string `json:"template"`
-	// Defaults to None
-	Format SecretFormat
-	// For tracking purposes
-	CorrelationId string `json:"correlationId"`
-}
+type SecretEncrypted = api.SecretEncrypted
+
+// SecretMeta represents the metadata of the secret.
+type SecretMeta = api.SecretMeta
+
+// SecretStored represents a secret stored in VSecM Safe.
+type SecretStored = api.SecretStored
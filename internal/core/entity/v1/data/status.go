@@ -5,15 +5,19 @@
 
 package data
 
-import "sync"
+import (
+	"sync"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+)
 
 // InitStatus is the initialization status of VSecM Sentinel
 // and other VSecM components.
-type InitStatus string
+type InitStatus = api.InitStatus
 
-var (
-	Pending InitStatus = "pending"
-	Ready   InitStatus = "ready"
+const (
+	Pending = api.Pending
+	Ready   = api.Ready
 )
 
 // Status is a struct representing the current state of the secret manager,
@@ -31,3 +31,58 @@ func PollIntervalForInitContainer() time.Duration {
 
 	return time.Duration(i) * time.Millisecond
 }
+
+// HealthBindAddrForInitContainer returns the address that the init container
+// serves its health endpoints on, as given by the
+// VSECM_INIT_CONTAINER_HEALTH_BIND_ADDR environment variable. It returns an
+// empty string if the variable is not set.
+func HealthBindAddrForInitContainer() string {
+	return env.Value(env.VSecMInitContainerHealthBindAddr)
+}
+
+// TimeoutForInitContainer returns how long the init container waits for the
+// secret, as given in milliseconds by the VSECM_INIT_CONTAINER_TIMEOUT
+// environment variable. It returns zero, meaning "no timeout", if the
+// variable is not set or cannot be parsed.
+func TimeoutForInitContainer() time.Duration {
+	p := env.Value(env.VSecMInitContainerTimeout)
+	if p == "" {
+		return 0
+	}
+
+	i, err := strconv.ParseInt(p, 10, 64)
+	if err != nil || i < 0 {
+		return 0
+	}
+
+	return time.Duration(i) * time.Millisecond
+}
+
+// FailureExitCodeForInitContainer returns the exit code of an init container
+// that gives up waiting for the secret, as given by the
+// VSECM_INIT_CONTAINER_FAILURE_EXIT_CODE environment variable. It returns 1
+// if the variable is not set, or if it is not a valid non-zero exit code.
+func FailureExitCodeForInitContainer() int {
+	d, _ := strconv.Atoi(string(env.VSecMInitContainerFailureExitCodeDefault))
+
+	p := env.Value(env.VSecMInitContainerFailureExitCode)
+	if p == "" {
+		return d
+	}
+
+	i, err := strconv.Atoi(p)
+	if err != nil || i <= 0 || i > 255 {
+		return d
+	}
+
+	return i
+}
+
+// PersistSecretForInitContainer tells whether the init container writes the
+// secret to the outputs of the sidecar before it exits, as given by the
+// VSECM_INIT_CONTAINER_PERSIST_SECRET environment variable. It returns false
+// if the variable is not set or cannot be parsed.
+func PersistSecretForInitContainer() bool {
+	b, _ := strconv.ParseBool(env.Value(env.VSecMInitContainerPersistSecret))
+	return b
+}
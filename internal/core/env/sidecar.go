@@ -6,6 +6,10 @@
 package env
 
 import (
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/spiffe/vsecm-sdk-go/internal/core/constants/env"
 )
 
@@ -19,3 +23,136 @@ func SecretsPathForSidecar() string {
 	}
 	return p
 }
+
+// OutputsForSidecar returns the YAML or JSON list of the outputs of the
+// sidecar, as given by the VSECM_SIDECAR_OUTPUTS environment variable.
+// It returns an empty string if the variable is not set.
+func OutputsForSidecar() string {
+	return env.Value(env.VSecMSidecarOutputs)
+}
+
+// OutputsFileForSidecar returns the path of the file that lists the outputs
+// of the sidecar, as given by the VSECM_SIDECAR_OUTPUTS_FILE environment
+// variable. It returns an empty string if the variable is not set.
+func OutputsFileForSidecar() string {
+	return env.Value(env.VSecMSidecarOutputsFile)
+}
+
+// HooksForSidecar returns the YAML or JSON list of the hooks that the
+// sidecar runs after the secret changes, as given by the VSECM_SIDECAR_HOOKS
+// environment variable. It returns an empty string if the variable is not
+// set.
+func HooksForSidecar() string {
+	return env.Value(env.VSecMSidecarHooks)
+}
+
+// HooksFileForSidecar returns the path of the file that lists the hooks of
+// the sidecar, as given by the VSECM_SIDECAR_HOOKS_FILE environment variable.
+// It returns an empty string if the variable is not set.
+func HooksFileForSidecar() string {
+	return env.Value(env.VSecMSidecarHooksFile)
+}
+
+// DeletePolicyForSidecar returns what the sidecar does when the secret has
+// been deleted, as given by the VSECM_SIDECAR_DELETE_POLICY environment
+// variable. It returns an empty string if the variable is not set.
+func DeletePolicyForSidecar() string {
+	return env.Value(env.VSecMSidecarDeletePolicy)
+}
+
+// DeleteGracePeriodForSidecar returns how long the sidecar keeps a deleted
+// secret, as given in milliseconds by the VSECM_SIDECAR_DELETE_GRACE_PERIOD
+// environment variable. It returns zero if the variable is not set or cannot
+// be parsed.
+func DeleteGracePeriodForSidecar() time.Duration {
+	p := env.Value(env.VSecMSidecarDeleteGracePeriod)
+	if p == "" {
+		return 0
+	}
+
+	i, err := strconv.ParseInt(p, 10, 64)
+	if err != nil || i < 0 {
+		return 0
+	}
+
+	return time.Duration(i) * time.Millisecond
+}
+
+// TombstonePathForSidecar returns the path of the file that the sidecar
+// creates when the secret has been deleted, as given by the
+// VSECM_SIDECAR_TOMBSTONE_PATH environment variable. It returns an empty
+// string if the variable is not set.
+func TombstonePathForSidecar() string {
+	return env.Value(env.VSecMSidecarTombstonePath)
+}
+
+// HealthBindAddrForSidecar returns the address that the sidecar serves its
+// health endpoints on, as given by the VSECM_SIDECAR_HEALTH_BIND_ADDR
+// environment variable. It returns an empty string if the variable is not
+// set.
+func HealthBindAddrForSidecar() string {
+	return env.Value(env.VSecMSidecarHealthBindAddr)
+}
+
+// StalePollsForSidecar returns the number of poll intervals without a
+// successful poll after which the sidecar is not ready anymore, as given by
+// the VSECM_SIDECAR_STALE_POLLS environment variable. It returns zero, so
+// that the default applies, if the variable is not set or cannot be parsed.
+func StalePollsForSidecar() int {
+	p := env.Value(env.VSecMSidecarStalePolls)
+	if p == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(p)
+	if err != nil || i < 0 {
+		return 0
+	}
+
+	return i
+}
+
+// SecretsFileModeForSidecar returns the permissions of the secrets file
+// written by the sidecar. The mode is given in octal by the
+// VSECM_SIDECAR_SECRETS_FILE_MODE environment variable (e.g. "0640"), with a
+// default value of "0600" if the variable is not set or cannot be parsed.
+func SecretsFileModeForSidecar() os.FileMode {
+	p := env.Value(env.VSecMSidecarSecretsFileMode)
+	d, _ := strconv.ParseUint(
+		string(env.VSecMSidecarSecretsFileModeDefault), 8, 32,
+	)
+	if p == "" {
+		return os.FileMode(d)
+	}
+
+	m, err := strconv.ParseUint(p, 8, 32)
+	if err != nil || m > uint64(os.ModePerm) {
+		return os.FileMode(d)
+	}
+
+	return os.FileMode(m)
+}
+
+// SecretsFileOwnerForSidecar returns the user and the group ids that the
+// sidecar gives the secrets file to. They are determined by the
+// VSECM_SIDECAR_SECRETS_FILE_UID and VSECM_SIDECAR_SECRETS_FILE_GID
+// environment variables. An id is -1, meaning "leave it as is", if its
+// variable is not set or cannot be parsed.
+func SecretsFileOwnerForSidecar() (uid, gid int) {
+	return idFromEnv(env.VSecMSidecarSecretsFileUid),
+		idFromEnv(env.VSecMSidecarSecretsFileGid)
+}
+
+func idFromEnv(name env.VarName) int {
+	p := env.Value(name)
+	if p == "" {
+		return -1
+	}
+
+	i, err := strconv.Atoi(p)
+	if err != nil || i < 0 {
+		return -1
+	}
+
+	return i
+}
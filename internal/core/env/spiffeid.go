@@ -21,6 +21,10 @@ func SpiffeIdPrefixForSafe() string {
 	return p
 }
 
+// SpiffeIdPrefixForClerk returns the prefix for the SPIFFE ID of the clerk
+// workloads; that is, the workloads that can store secrets. The prefix is
+// obtained from the environment variable VSECM_SPIFFEID_PREFIX_CLERK. If the
+// variable is not set, the default prefix is used.
 func SpiffeIdPrefixForClerk() string {
 	p := env.Value(env.VSecMSpiffeIdPrefixClerk)
 	if p == "" {
@@ -29,6 +33,30 @@ func SpiffeIdPrefixForClerk() string {
 	return p
 }
 
+// SpiffeIdPrefixForScout returns the prefix for the Scout SPIFFE ID.
+// The prefix is obtained from the environment variable
+// VSECM_SPIFFEID_PREFIX_SCOUT. If the variable is not set, the default
+// prefix is used.
+func SpiffeIdPrefixForScout() string {
+	p := env.Value(env.VSecMSpiffeIdPrefixScout)
+	if p == "" {
+		p = string(env.VSecMSpiffeIdPrefixScoutDefault)
+	}
+	return p
+}
+
+// SpiffeIdPrefixForSentinel returns the prefix for the Sentinel SPIFFE ID.
+// The prefix is obtained from the environment variable
+// VSECM_SPIFFEID_PREFIX_SENTINEL. If the variable is not set, the default
+// prefix is used.
+func SpiffeIdPrefixForSentinel() string {
+	p := env.Value(env.VSecMSpiffeIdPrefixSentinel)
+	if p == "" {
+		p = string(env.VSecMSpiffeIdPrefixSentinelDefault)
+	}
+	return p
+}
+
 // SpiffeIdPrefixForWorkload returns the prefix for the Workload's SPIFFE ID.
 // The prefix is obtained from the environment variable
 // VSECM_SPIFFEID_PREFIX_WORKLOAD.
@@ -46,6 +46,28 @@ func JsonToYaml(js string) (string, error) {
 	return string(yamlBytes), nil
 }
 
+// YamlToJson converts a YAML string into a JSON string.
+//
+// The function takes a YAML string as input and attempts to unmarshal it
+// into an empty interface. If the unmarshalling is successful, it marshals
+// the data back into a JSON string using the JSON package.
+//
+// On success, the function returns the JSON string and a nil error.
+// If there is any error during the conversion, it returns an empty string
+// and the corresponding error.
+func YamlToJson(yml string) (string, error) {
+	var yamlObj any
+	err := yaml.Unmarshal([]byte(yml), &yamlObj)
+	if err != nil {
+		return "", err
+	}
+	jsonBytes, err := json.Marshal(yamlObj)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
 // TryParse attempts to parse and execute a template with the given JSON string.
 //
 // The function takes two string inputs - a template string (tmpStr) and a JSON
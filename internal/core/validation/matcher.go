@@ -0,0 +1,222 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	e "github.com/spiffe/vsecm-sdk-go/internal/core/constants/env"
+)
+
+// Matcher tells whether a SPIFFE ID matches a pattern of the Rules.
+//
+// A Matcher is compiled once by NewMatcher, so matching neither compiles
+// regular expressions nor panics. The zero Matcher matches nothing.
+type Matcher struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+// NewMatcher compiles pattern, which is either a plain SPIFFE ID prefix, or
+// a regular expression that starts with `^spiffe://$trustDomain/`.
+//
+// Any SPIFFE ID regular expression matcher shall start with the
+// `^spiffe://$trustDomain` prefix for extra security; other patterns are
+// plain prefixes. So, a regular expression of another trust domain never
+// matches.
+//
+// NewMatcher returns an error if pattern is empty, or if it is a regular
+// expression that does not compile.
+func NewMatcher(trustDomain, pattern string) (Matcher, error) {
+	if pattern == "" {
+		return Matcher{}, errors.New("empty pattern")
+	}
+
+	if !strings.HasPrefix(pattern, "^spiffe://"+trustDomain+"/") {
+		return Matcher{prefix: pattern}, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Matcher{}, err
+	}
+
+	return Matcher{re: re}, nil
+}
+
+// Match reports whether spiffeid matches the Matcher.
+func (m Matcher) Match(spiffeid string) bool {
+	switch {
+	case m.re != nil:
+		return m.re.MatchString(spiffeid)
+	case m.prefix != "":
+		return strings.HasPrefix(spiffeid, m.prefix)
+	default:
+		return false
+	}
+}
+
+// String returns the pattern that the Matcher has been compiled from.
+func (m Matcher) String() string {
+	if m.re != nil {
+		return m.re.String()
+	}
+	return m.prefix
+}
+
+// Role is a kind of workload that VSecM tells apart by its SPIFFE ID.
+type Role string
+
+// The roles that VSecM knows about. Any other Role is user-defined.
+const (
+	RoleWorkload Role = "workload"
+	RoleSafe     Role = "safe"
+	RoleClerk    Role = "clerk"
+	RoleSentinel Role = "sentinel"
+	RoleScout    Role = "scout"
+)
+
+// Matchers are the compiled Rules. Create them with Rules.Compile, once, when
+// the configuration is loaded; they are safe for concurrent use.
+type Matchers struct {
+	trustDomain  string
+	workloadName *regexp.Regexp
+	roles        map[Role]Matcher
+}
+
+// Compile compiles the Rules into Matchers. It returns an error that names
+// the environment variable, or the role, of the first pattern that is
+// invalid.
+func (r Rules) Compile() (*Matchers, error) {
+	m := &Matchers{
+		trustDomain: r.TrustDomain,
+		roles:       make(map[Role]Matcher, 5+len(r.Roles)),
+	}
+
+	builtin := []struct {
+		role    Role
+		name    e.VarName
+		pattern string
+	}{
+		{RoleWorkload, e.VSecMSpiffeIdPrefixWorkload, r.WorkloadPrefix},
+		{RoleSafe, e.VSecMSpiffeIdPrefixSafe, r.SafePrefix},
+		{RoleClerk, e.VSecMSpiffeIdPrefixClerk, r.ClerkPrefix},
+		{RoleSentinel, e.VSecMSpiffeIdPrefixSentinel, r.SentinelPrefix},
+		{RoleScout, e.VSecMSpiffeIdPrefixScout, r.ScoutPrefix},
+	}
+	for _, b := range builtin {
+		mt, err := NewMatcher(r.TrustDomain, b.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", b.name, err)
+		}
+		m.roles[b.role] = mt
+	}
+
+	for role, pattern := range r.Roles {
+		if role == "" {
+			return nil, errors.New("invalid role: empty name")
+		}
+		if _, ok := m.roles[role]; ok {
+			return nil, fmt.Errorf("invalid role %s: it is built in", role)
+		}
+
+		mt, err := NewMatcher(r.TrustDomain, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role %s: %w", role, err)
+		}
+		m.roles[role] = mt
+	}
+
+	// The workload name is extracted from the SPIFFE ID, so it is always a
+	// regular expression. Along with a plain workload prefix, it is the only
+	// check of the SPIFFE ID; so it shall be anchored to the trust domain.
+	name, err := regexp.Compile(r.WorkloadNameRegExp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", e.VSecMWorkloadNameRegExp, err)
+	}
+	if m.roles[RoleWorkload].re == nil &&
+		!strings.HasPrefix(r.WorkloadNameRegExp, "^spiffe://"+r.TrustDomain+"/") {
+		return nil, fmt.Errorf("invalid %s: expected ^spiffe://%s/...",
+			e.VSecMWorkloadNameRegExp, r.TrustDomain)
+	}
+	m.workloadName = name
+
+	return m, nil
+}
+
+// Has reports whether role is known to the Matchers.
+func (m *Matchers) Has(role Role) bool {
+	_, ok := m.roles[role]
+	return ok
+}
+
+// Is reports whether a given SPIFFE ID has role. Every role other than
+// RoleWorkload is also a workload; so the SPIFFE ID shall match both. An
+// unknown role matches nothing.
+func (m *Matchers) Is(role Role, spiffeid string) bool {
+	if !m.IsWorkload(spiffeid) {
+		return false
+	}
+	if role == RoleWorkload {
+		return true
+	}
+
+	return m.roles[role].Match(spiffeid)
+}
+
+// IsAny reports whether a given SPIFFE ID has any of roles.
+func (m *Matchers) IsAny(roles []Role, spiffeid string) bool {
+	for _, role := range roles {
+		if m.Is(role, spiffeid) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkload checks if a given SPIFFE ID belongs to a workload; that is, if
+// it belongs to the trust domain, if the workload name can be extracted from
+// it, and if it matches the workload pattern.
+func (m *Matchers) IsWorkload(spiffeid string) bool {
+	if !strings.HasPrefix(spiffeid, "spiffe://"+m.trustDomain+"/") {
+		return false
+	}
+
+	if len(m.workloadName.FindStringSubmatch(spiffeid)) == 0 {
+		return false
+	}
+
+	return m.roles[RoleWorkload].Match(spiffeid)
+}
+
+// IsSafe checks if a given SPIFFE ID belongs to VSecM Safe.
+func (m *Matchers) IsSafe(spiffeid string) bool {
+	return m.Is(RoleSafe, spiffeid)
+}
+
+// IsClerk checks if a given SPIFFE ID belongs to a clerk workload.
+func (m *Matchers) IsClerk(spiffeid string) bool {
+	return m.Is(RoleClerk, spiffeid)
+}
+
+// IsSentinel checks if a given SPIFFE ID belongs to VSecM Sentinel.
+func (m *Matchers) IsSentinel(spiffeid string) bool {
+	return m.Is(RoleSentinel, spiffeid)
+}
+
+// IsScout checks if a given SPIFFE ID belongs to VSecM Scout.
+func (m *Matchers) IsScout(spiffeid string) bool {
+	return m.Is(RoleScout, spiffeid)
+}
+
+// IsPrivileged checks if a given SPIFFE ID belongs to a clerk workload or to
+// VSecM Sentinel.
+func (m *Matchers) IsPrivileged(spiffeid string) bool {
+	return m.IsAny([]Role{RoleClerk, RoleSentinel}, spiffeid)
+}
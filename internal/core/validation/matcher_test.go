@@ -0,0 +1,297 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	e "github.com/spiffe/vsecm-sdk-go/internal/core/constants/env"
+)
+
+const (
+	safeID     = "spiffe://vsecm.com/workload/vsecm-safe/ns/vsecm-system/sa/vsecm-safe/n/safe-0"
+	clerkID    = "spiffe://vsecm.com/workload/vsecm-clerk/ns/vsecm-clerk/sa/vsecm-clerk/n/clerk-0"
+	sentinelID = "spiffe://vsecm.com/workload/vsecm-sentinel/ns/vsecm-system/sa/vsecm-sentinel/n/sentinel-0"
+	scoutID    = "spiffe://vsecm.com/workload/vsecm-scout/ns/vsecm-system/sa/vsecm-scout/n/scout-0"
+	workloadID = "spiffe://vsecm.com/workload/example/ns/default/sa/example/n/example-0"
+	operatorID = "spiffe://vsecm.com/workload/operator/ns/default/sa/operator/n/operator-0"
+	foreignID  = "spiffe://example.org/workload/example/ns/default/sa/example/n/example-0"
+)
+
+// defaultRules are the Rules that the default environment describes, along
+// with a user-defined role.
+func defaultRules() Rules {
+	return Rules{
+		TrustDomain:        string(e.SpiffeTrustDomainDefault),
+		WorkloadPrefix:     string(e.VSecMSpiffeIdPrefixWorkloadDefault),
+		WorkloadNameRegExp: string(e.VSecMNameRegExpForWorkloadDefault),
+		SafePrefix:         string(e.VSecMSpiffeIdPrefixSafeDefault),
+		ClerkPrefix:        string(e.VSecMSpiffeIdPrefixClerkDefault),
+		SentinelPrefix:     string(e.VSecMSpiffeIdPrefixSentinelDefault),
+		ScoutPrefix:        string(e.VSecMSpiffeIdPrefixScoutDefault),
+		Roles: map[Role]string{
+			"operator": "^spiffe://vsecm.com/workload/operator/ns/[^/]+/sa/[^/]+/n/[^/]+$",
+		},
+	}
+}
+
+func TestNewMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		id      string
+		match   bool
+		err     bool
+	}{
+		{
+			name:    "regexp matches",
+			pattern: "^spiffe://vsecm.com/workload/[^/]+/ns/default/",
+			id:      workloadID,
+			match:   true,
+		},
+		{
+			name:    "regexp does not match",
+			pattern: "^spiffe://vsecm.com/workload/[^/]+/ns/vsecm-system/",
+			id:      workloadID,
+		},
+		{
+			name:    "plain prefix matches",
+			pattern: "spiffe://vsecm.com/workload/example/",
+			id:      workloadID,
+			match:   true,
+		},
+		{
+			name:    "plain prefix is not a regexp",
+			pattern: "spiffe://vsecm.com/workload/.*",
+			id:      workloadID,
+		},
+		{
+			name:    "regexp of another trust domain is a plain prefix",
+			pattern: "^spiffe://example.org/.*$",
+			id:      foreignID,
+		},
+		{
+			name:    "plain prefix of another trust domain",
+			pattern: "spiffe://example.org/",
+			id:      workloadID,
+		},
+		{
+			name: "empty pattern",
+			err:  true,
+		},
+		{
+			name:    "invalid regexp",
+			pattern: "^spiffe://vsecm.com/workload/(",
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher("vsecm.com", tt.pattern)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("NewMatcher(%q) succeeded, want an error", tt.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMatcher(%q): %v", tt.pattern, err)
+			}
+
+			if got := m.Match(tt.id); got != tt.match {
+				t.Errorf("Match(%q) = %v, want %v", tt.id, got, tt.match)
+			}
+			if got := m.String(); got != tt.pattern {
+				t.Errorf("String() = %q, want %q", got, tt.pattern)
+			}
+		})
+	}
+
+	var zero Matcher
+	if zero.Match(workloadID) {
+		t.Errorf("the zero Matcher matches %q", workloadID)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Rules)
+		err    string
+	}{
+		{
+			name:   "defaults",
+			modify: func(r *Rules) {},
+		},
+		{
+			name: "unanchored name regexp with a plain workload prefix",
+			modify: func(r *Rules) {
+				r.WorkloadPrefix = "spiffe://vsecm.com/workload/"
+				r.WorkloadNameRegExp = "/workload/([^/]+)/"
+			},
+			err: string(e.VSecMWorkloadNameRegExp),
+		},
+		{
+			name: "name regexp of another trust domain",
+			modify: func(r *Rules) {
+				r.WorkloadPrefix = "spiffe://vsecm.com/workload/"
+				r.WorkloadNameRegExp = "^spiffe://example.org/workload/([^/]+)/"
+			},
+			err: string(e.VSecMWorkloadNameRegExp),
+		},
+		{
+			name: "unanchored name regexp with a workload regexp",
+			modify: func(r *Rules) {
+				r.WorkloadNameRegExp = "/workload/([^/]+)/"
+			},
+		},
+		{
+			name: "invalid name regexp",
+			modify: func(r *Rules) {
+				r.WorkloadNameRegExp = "^spiffe://vsecm.com/workload/("
+			},
+			err: string(e.VSecMWorkloadNameRegExp),
+		},
+		{
+			name: "empty safe pattern",
+			modify: func(r *Rules) {
+				r.SafePrefix = ""
+			},
+			err: string(e.VSecMSpiffeIdPrefixSafe),
+		},
+		{
+			name: "invalid workload regexp",
+			modify: func(r *Rules) {
+				r.WorkloadPrefix = "^spiffe://vsecm.com/workload/("
+			},
+			err: string(e.VSecMSpiffeIdPrefixWorkload),
+		},
+		{
+			name: "user-defined role collides with a built-in one",
+			modify: func(r *Rules) {
+				r.Roles = map[Role]string{RoleClerk: "spiffe://vsecm.com/"}
+			},
+			err: "it is built in",
+		},
+		{
+			name: "user-defined role without a name",
+			modify: func(r *Rules) {
+				r.Roles = map[Role]string{"": "spiffe://vsecm.com/"}
+			},
+			err: "empty name",
+		},
+		{
+			name: "user-defined role with an invalid regexp",
+			modify: func(r *Rules) {
+				r.Roles = map[Role]string{"operator": "^spiffe://vsecm.com/("}
+			},
+			err: "invalid role operator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := defaultRules()
+			tt.modify(&r)
+
+			_, err := r.Compile()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("Compile: %v", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("Compile succeeded, want an error about %s", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Fatalf("Compile: %v, want an error about %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMatchersIs(t *testing.T) {
+	m, err := defaultRules().Compile()
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		id    string
+		roles []Role
+	}{
+		{id: workloadID, roles: []Role{RoleWorkload}},
+		{id: safeID, roles: []Role{RoleWorkload, RoleSafe}},
+		{id: clerkID, roles: []Role{RoleWorkload, RoleClerk}},
+		{id: sentinelID, roles: []Role{RoleWorkload, RoleSentinel}},
+		{id: scoutID, roles: []Role{RoleWorkload, RoleScout}},
+		{id: operatorID, roles: []Role{RoleWorkload, "operator"}},
+		{id: foreignID},
+		{id: "spiffe://vsecm.com/workload/example"},
+		{id: ""},
+	}
+
+	all := []Role{
+		RoleWorkload, RoleSafe, RoleClerk, RoleSentinel, RoleScout,
+		"operator", "unknown",
+	}
+
+	for _, tt := range tests {
+		want := map[Role]bool{}
+		for _, r := range tt.roles {
+			want[r] = true
+		}
+
+		for _, role := range all {
+			if got := m.Is(role, tt.id); got != want[role] {
+				t.Errorf("Is(%s, %q) = %v, want %v", role, tt.id, got, want[role])
+			}
+		}
+
+		privileged := want[RoleClerk] || want[RoleSentinel]
+		if got := m.IsPrivileged(tt.id); got != privileged {
+			t.Errorf("IsPrivileged(%q) = %v, want %v", tt.id, got, privileged)
+		}
+	}
+
+	if !m.Has("operator") || m.Has("unknown") {
+		t.Errorf("Has does not tell the known roles apart")
+	}
+}
+
+func TestMatchersIsPlainPrefixes(t *testing.T) {
+	r := defaultRules()
+	r.WorkloadPrefix = "spiffe://vsecm.com/workload/"
+	r.ClerkPrefix = "spiffe://vsecm.com/workload/vsecm-clerk/"
+	r.Roles = map[Role]string{"operator": "spiffe://vsecm.com/workload/operator/"}
+
+	m, err := r.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		role Role
+		id   string
+		want bool
+	}{
+		{"workload", RoleWorkload, workloadID, true},
+		{"clerk", RoleClerk, clerkID, true},
+		{"clerk prefix is not a clerk", RoleClerk, workloadID, false},
+		{"user-defined role", "operator", operatorID, true},
+		{"user-defined role needs its prefix", "operator", workloadID, false},
+		{"another trust domain", RoleWorkload, foreignID, false},
+		{"no workload name", RoleWorkload, "spiffe://vsecm.com/workload/x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Is(tt.role, tt.id); got != tt.want {
+				t.Errorf("Is(%s, %q) = %v, want %v", tt.role, tt.id, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package backoff
 
 import (
+	"context"
 	"github.com/spiffe/vsecm-sdk-go/internal/debug"
 	"math"
 	"math/rand"
@@ -27,7 +28,7 @@ type Strategy struct {
 	// Default is false
 	Exponential bool
 	// Maximum duration to wait between retries (in milliseconds)
-	// Default is 10 seconds
+	// Default is 10 seconds with exponential backoff, and no limit otherwise
 	MaxWait time.Duration
 }
 
@@ -70,12 +71,27 @@ type Strategy struct {
 //	    fmt.Println("Failed to connect to database after retries:", err)
 //	}
 func Retry(scope string, f func() error, s Strategy) error {
+	return RetryContext(context.Background(), scope, f, s)
+}
+
+// RetryContext is like Retry, but it stops retrying as soon as ctx is done.
+//
+// The context is checked before each attempt and while sleeping between
+// attempts. If ctx is done, RetryContext returns ctx.Err() without waiting
+// for the remaining retries.
+func RetryContext(
+	ctx context.Context, scope string, f func() error, s Strategy,
+) error {
 	s = withDefaults(s)
 	var err error
 
 	debug.Log("Retry: starting retry loop")
 
 	for i := 0; i <= int(s.MaxRetries); i++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
 		err = f()
 
 		debug.Log("Retry: executed the function")
@@ -103,13 +119,19 @@ func Retry(scope string, f func() error, s Strategy) error {
 		// Some randomness to avoid the thundering herd problem.
 		jitter := rand.Intn(int(sDelayMs))
 		delay += time.Duration(jitter) * time.Millisecond
-		if delay > s.MaxWait {
+		if s.MaxWait > 0 && delay > s.MaxWait {
 			delay = s.MaxWait
 		}
 
 		debug.Log("Retry: will sleep:", delay)
 
-		time.Sleep(delay)
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
 
 		debug.Log(
 			"Retrying after", delay, "ms for the scope",
@@ -0,0 +1,120 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// Owner is the owner to give to a file. A negative UID or GID leaves the
+// corresponding owner unchanged.
+type Owner struct {
+	UID int
+	GID int
+}
+
+// NoOwner keeps the owner of the file as is; that is, the user and the group
+// of the current process.
+var NoOwner = Owner{UID: -1, GID: -1}
+
+// WriteAtomic writes data to path in a way that readers of path either see
+// the old content, or the new content, and never a partial one.
+//
+// It writes data to a temporary file in the same directory as path, flushes
+// it to disk, sets its mode and its owner, and then renames it over path.
+// The temporary file is removed if anything goes wrong, and its file
+// descriptor is always closed.
+func WriteAtomic(path string, data []byte, mode os.FileMode, owner Owner) error {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return errors.Join(err, errors.New("error creating temporary file"))
+	}
+	tmp := f.Name()
+
+	renamed := false
+	defer func() {
+		if renamed {
+			return
+		}
+		err := os.Remove(tmp)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			debug.Log("WriteAtomic: problem removing temporary file: ",
+				err.Error())
+		}
+	}()
+
+	err = write(f, data, mode, owner)
+	cErr := f.Close()
+	if err != nil {
+		return err
+	}
+	if cErr != nil {
+		return errors.Join(cErr, errors.New("error closing temporary file"))
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return errors.Join(err, errors.New("error renaming temporary file"))
+	}
+	renamed = true
+
+	syncDir(dir)
+
+	return nil
+}
+
+func write(f *os.File, data []byte, mode os.FileMode, owner Owner) error {
+	_, err := f.Write(data)
+	if err != nil {
+		return errors.Join(err, errors.New("error saving data"))
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return errors.Join(err, errors.New("error flushing data"))
+	}
+
+	err = f.Chmod(mode)
+	if err != nil {
+		return errors.Join(err, errors.New("error setting file mode"))
+	}
+
+	if owner.UID >= 0 || owner.GID >= 0 {
+		err = f.Chown(owner.UID, owner.GID)
+		if err != nil {
+			return errors.Join(err, errors.New("error setting file owner"))
+		}
+	}
+
+	return nil
+}
+
+// syncDir flushes the directory entry of the renamed file to disk, so that
+// the rename survives a crash. This is best effort; not every platform
+// supports syncing a directory.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = d.Close()
+	}()
+
+	err = d.Sync()
+	if err != nil {
+		debug.Log("WriteAtomic: problem syncing directory: ", err.Error())
+	}
+}
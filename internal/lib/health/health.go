@@ -0,0 +1,98 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// Checker tells how a long-running loop is doing.
+type Checker interface {
+	// Live returns nil if the loop is running.
+	Live() error
+	// Ready returns nil if the loop has done its job at least once, and
+	// its result is still fresh.
+	Ready() error
+	// Status returns the details of the loop, to be rendered as JSON.
+	Status() any
+}
+
+// Serve starts an HTTP server on addr that exposes c:
+//
+//   - /healthz answers 200 if c.Live returns nil, and 503 otherwise;
+//   - /readyz answers 200 if c.Ready returns nil, and 503 otherwise;
+//   - /status answers 200 with c.Status as JSON.
+//
+// Serve returns once the server is listening; or an error if it cannot
+// listen on addr. The server is shut down when ctx is done; the returned
+// channel is closed once it is, and addr is free again.
+func Serve(ctx context.Context, addr string, c Checker) (<-chan struct{}, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("unable to start health server"))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", check(c.Live))
+	mux.HandleFunc("/readyz", check(c.Ready))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(c.Status())
+		if err != nil {
+			debug.Log("health: problem writing status: ", err.Error())
+		}
+	})
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			debug.Log("health: server stopped: ", err.Error())
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+
+		sCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		err := srv.Shutdown(sCtx)
+		if err != nil {
+			_ = srv.Close()
+		}
+	}()
+
+	return done, nil
+}
+
+func check(f func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+		err := f()
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(err.Error() + "\n"))
+			return
+		}
+
+		_, _ = w.Write([]byte("ok\n"))
+	}
+}
@@ -0,0 +1,340 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/core/validation"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// Client talks to VSecM Safe on behalf of the workload.
+//
+// A Client owns a single SPIFFE X509Source and a single mTLS transport that
+// are reused across calls. The X509Source keeps the workload's SVID and trust
+// bundle up-to-date in the background, so there is no need to go back to the
+// SPIFFE Workload API, or to perform a fresh TLS handshake, on every call.
+//
+// A Client is safe for concurrent use by multiple goroutines. Create it once
+// with New, share it, and release its resources with Close when done.
+type Client struct {
+	source *workloadapi.X509Source
+	http   *http.Client
+
+	cfg      Config
+	matchers *validation.Matchers
+	// grants are the roles that may perform each Operation.
+	grants map[Operation][]Role
+
+	// state backs FetchIfChanged.
+	state fetchState
+	// status is the health of the Watch loop of the Client.
+	status watchStatus
+}
+
+// New creates a Client that is ready to talk to VSecM Safe.
+//
+// The Client starts from DefaultConfig, and opts override it in order.
+//
+// New connects to the SPIFFE Workload API and blocks until the workload's
+// X.509 SVID is available, or until ctx is done; in which case it returns
+// ctx.Err(). The X509Source outlives ctx and is only released by Close.
+//
+// The SPIFFE ID patterns, the Grants, and the PollInterval of the Config are
+// checked before that; if any of them is invalid, New returns an error that
+// wraps ErrInvalidConfig right away.
+//
+//	client, err := sentry.New(ctx)
+//	if err != nil {
+//	    log.Fatalf("Failed to create client: %v", err)
+//	}
+//	defer client.Close()
+//
+//	secret, err := client.Fetch(ctx)
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	cfg := DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	err := cfg.check("new")
+	if err != nil {
+		return nil, err
+	}
+
+	// Misconfigured SPIFFE ID patterns are reported here, rather than
+	// during a TLS handshake.
+	matchers, grants, err := cfg.authorization("new")
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := workloadapi.NewX509Source(
+		ctx, workloadapi.WithClientOptions(
+			workloadapi.WithAddr(cfg.SpiffeSocket),
+		),
+	)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.Join(
+			err,
+			fmt.Errorf(
+				"new: %w: failed getting SVID Bundle from the SPIFFE Workload API",
+				ErrSVIDUnavailable,
+			),
+		)
+	}
+
+	authorizer := tlsconfig.AdaptMatcher(func(id spiffeid.ID) error {
+		if matchers.IsSafe(id.String()) {
+			return nil
+		}
+
+		return fmt.Errorf("sentry: %w: I don't know you, and it's crazy: '%s'",
+			ErrUntrustedSafe, id.String())
+	})
+
+	return &Client{
+		source: source,
+		http: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsconfig.MTLSClientConfig(
+					source, source, authorizer,
+				),
+			},
+		},
+		cfg:      cfg,
+		matchers: matchers,
+		grants:   grants,
+	}, nil
+}
+
+// Config returns the Config that the Client has been created with.
+func (c *Client) Config() Config {
+	return c.cfg
+}
+
+// Close releases the X509Source and the idle connections held by the Client.
+// The Client cannot be used after Close.
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return c.source.Close()
+}
+
+// identify returns the SPIFFE ID of the current X.509 SVID of the workload,
+// after making sure that it has one of the roles that may perform op.
+func (c *Client) identify(op Operation) (string, error) {
+	scope := string(op)
+
+	svid, err := c.source.GetX509SVID()
+	if err != nil {
+		return "", errors.Join(
+			err,
+			fmt.Errorf("%s: %w", scope, ErrSVIDUnavailable),
+		)
+	}
+
+	id := svid.ID.String()
+	if !c.can(op)(id) {
+		return "", fmt.Errorf("%s: %w: '%s'", scope, ErrUntrustedWorkload, id)
+	}
+
+	return id, nil
+}
+
+// endpoint returns the absolute VSecM Safe URL for the given API path.
+// The path may carry a query string.
+func (c *Client) endpoint(path string) (string, error) {
+	p, query, _ := strings.Cut(path, "?")
+
+	u, err := url.JoinPath(c.cfg.SafeEndpoint, p)
+	if err != nil || query == "" {
+		return u, err
+	}
+
+	return u + "?" + query, nil
+}
+
+// send sends a request to the given VSecM Safe API path, and returns the
+// status code and the body of the response. If payload is not nil, it is
+// sent as the JSON body of the request.
+func (c *Client) send(
+	ctx context.Context, scope, method, path string, payload any,
+) (int, []byte, error) {
+	code, _, body, err := c.sendWithHeader(ctx, scope, method, path, payload, nil)
+	return code, body, err
+}
+
+// sendWithHeader is like send, but it also adds header to the request, and
+// returns the header of the response.
+func (c *Client) sendWithHeader(
+	ctx context.Context, scope, method, path string, payload any,
+	header http.Header,
+) (int, http.Header, []byte, error) {
+	p, err := c.endpoint(path)
+	if err != nil {
+		return 0, nil, nil, errors.Join(
+			err,
+			fmt.Errorf("%s: %w: problem generating server url",
+				scope, ErrInvalidRequest),
+		)
+	}
+
+	var rb io.Reader
+	if payload != nil {
+		md, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, nil, errors.Join(
+				err,
+				fmt.Errorf("%s: %w: problem generating the payload",
+					scope, ErrInvalidRequest),
+			)
+		}
+		rb = bytes.NewBuffer(md)
+	}
+
+	debug.Log("Sentry:"+scope, method, p)
+
+	req, err := http.NewRequestWithContext(ctx, method, p, rb)
+	if err != nil {
+		return 0, nil, nil, errors.Join(
+			err,
+			fmt.Errorf("%s: %w", scope, ErrInvalidRequest),
+		)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	r, err := c.http.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, nil, nil, ctxErr
+		}
+		if errors.Is(err, ErrUntrustedSafe) {
+			return 0, nil, nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		return 0, nil, nil, errors.Join(
+			err,
+			fmt.Errorf("%s: %w", scope, ErrSafeUnreachable),
+		)
+	}
+
+	defer func(b io.ReadCloser) {
+		err := b.Close()
+		if err != nil {
+			debug.Log("Sentry:"+scope,
+				"problem closing response body: ", err.Error())
+		}
+	}(r.Body)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, nil, nil, ctxErr
+		}
+		return 0, nil, nil, errors.Join(
+			err,
+			fmt.Errorf(
+				"%s: %w: unable to read the response body",
+				scope, ErrSafeUnreachable,
+			),
+		)
+	}
+
+	return r.StatusCode, r.Header, body, nil
+}
+
+// receive turns the response of VSecM Safe into v; or into an error if the
+// response reports a failure, either through a non-2xx status code, or
+// through the `err` field that every VSecM Safe response carries.
+//
+// v can be nil if the caller is not interested in the response body.
+func receive(scope string, code int, body []byte, v any) error {
+	// Best effort: the body may not even be JSON if things went south.
+	var gr api.GenericResponse
+	_ = json.Unmarshal(body, &gr)
+
+	if code < http.StatusOK || code >= http.StatusMultipleChoices {
+		se := &HTTPStatusError{Code: code, Body: string(body)}
+		if gr.Err != "" {
+			return fmt.Errorf("%s: %w",
+				scope, errors.Join(se, &ServerError{Err: gr.Err}))
+		}
+		return fmt.Errorf("%s: %w", scope, se)
+	}
+
+	if gr.Err != "" {
+		return fmt.Errorf("%s: %w", scope, &ServerError{Err: gr.Err})
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(body, v)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("%s: %w", scope, ErrInvalidResponse))
+	}
+
+	return nil
+}
+
+var (
+	// defaultClientLock is a 1-slot semaphore rather than a sync.Mutex, so
+	// that a caller can give up waiting for it when its ctx is done, while
+	// another caller is blocked in New.
+	defaultClientLock = make(chan struct{}, 1)
+	defaultClient     *Client
+)
+
+// getDefaultClient returns the Client that backs the package-level functions,
+// creating it on first use. If creation fails, the next call tries again.
+//
+// getDefaultClient returns ctx.Err() as soon as ctx is done, even if another
+// caller is still creating the Client.
+func getDefaultClient(ctx context.Context) (*Client, error) {
+	select {
+	case defaultClientLock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() {
+		<-defaultClientLock
+	}()
+
+	if defaultClient != nil {
+		return defaultClient, nil
+	}
+
+	c, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Log("Sentry: created the default client")
+
+	defaultClient = c
+	return c, nil
+}
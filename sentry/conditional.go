@@ -0,0 +1,116 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// fetchState remembers what a conditional fetch has last seen, so that the
+// next one can tell whether the secret has changed since.
+type fetchState struct {
+	lock sync.Mutex
+
+	seen bool
+	last api.SecretFetchResponse
+
+	// Validators that VSecM Safe has sent along with the last response,
+	// if any.
+	etag         string
+	lastModified string
+}
+
+// reset forgets the last response; the next fetch reports a change.
+func (s *fetchState) reset() {
+	s.seen = false
+	s.last = api.SecretFetchResponse{}
+	s.etag = ""
+	s.lastModified = ""
+}
+
+// FetchIfChanged is like Fetch, but it only reports the secret as changed if
+// it differs from what the previous call of FetchIfChanged on the same Client
+// has returned.
+//
+//	r, changed, err := client.FetchIfChanged(ctx)
+//	if err == nil && changed {
+//	    reload(r.Data)
+//	}
+//
+// If VSecM Safe has sent an ETag or a Last-Modified header before, the
+// request carries If-None-Match or If-Modified-Since, so that VSecM Safe can
+// answer with 304 Not Modified instead of sending the secret again.
+// Otherwise, the secret is considered unchanged if its Updated timestamp and
+// its Data are the same as the last time.
+//
+// When the secret is unchanged, FetchIfChanged returns the last known
+// response and false. An empty secret that has just been emptied is a change;
+// so "unchanged" and "empty" are never confused.
+func (c *Client) FetchIfChanged(
+	ctx context.Context,
+) (api.SecretFetchResponse, bool, error) {
+	return c.fetchIfChanged(ctx, &c.state)
+}
+
+func (c *Client) fetchIfChanged(
+	ctx context.Context, st *fetchState,
+) (api.SecretFetchResponse, bool, error) {
+	id, err := c.identify(OpFetch)
+	if err != nil {
+		return api.SecretFetchResponse{}, false, err
+	}
+
+	debug.Log("Sentry:FetchIfChanged svid:id: ", id)
+
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	header := http.Header{}
+	if st.seen && st.etag != "" {
+		header.Set("If-None-Match", st.etag)
+	}
+	if st.seen && st.lastModified != "" {
+		header.Set("If-Modified-Since", st.lastModified)
+	}
+
+	code, rh, body, err := c.sendWithHeader(
+		ctx, "fetch", http.MethodGet, "/workload/v1/secrets", nil, header,
+	)
+	if err != nil {
+		return api.SecretFetchResponse{}, false, err
+	}
+
+	if code == http.StatusNotModified && st.seen {
+		return st.last, false, nil
+	}
+
+	if code == http.StatusNotFound {
+		st.reset()
+		return api.SecretFetchResponse{}, false, ErrSecretNotFound
+	}
+
+	var sfr api.SecretFetchResponse
+	err = receive("fetch", code, body, &sfr)
+	if err != nil {
+		return api.SecretFetchResponse{}, false, err
+	}
+
+	changed := !st.seen ||
+		sfr.Updated != st.last.Updated ||
+		sfr.Data != st.last.Data
+
+	st.seen = true
+	st.last = sfr
+	st.etag = rh.Get("ETag")
+	st.lastModified = rh.Get("Last-Modified")
+
+	return sfr, changed, nil
+}
@@ -0,0 +1,427 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
+	"github.com/spiffe/vsecm-sdk-go/internal/core/validation"
+)
+
+// Config holds the settings that a Client uses to talk to VSecM Safe.
+//
+// The zero value is not useful; start from DefaultConfig, which reads the
+// environment variables, and override fields with Options. Each Client keeps
+// its own copy of the Config, so differently-configured Clients can live side
+// by side in the same process.
+type Config struct {
+	// SafeEndpoint is the base URL of the VSecM Safe API.
+	// Env: VSECM_SAFE_ENDPOINT_URL
+	SafeEndpoint string
+
+	// SpiffeSocket is the address of the SPIFFE Workload API.
+	// Env: SPIFFE_ENDPOINT_SOCKET
+	SpiffeSocket string
+
+	// TrustDomain is the SPIFFE trust domain of VSecM.
+	// Env: SPIFFE_TRUST_DOMAIN
+	TrustDomain string
+
+	// SafeIDPrefix is the SPIFFE ID prefix, or the `^spiffe://...` regular
+	// expression, that identifies VSecM Safe.
+	// Env: VSECM_SPIFFEID_PREFIX_SAFE
+	SafeIDPrefix string
+
+	// ClerkIDPrefix is the SPIFFE ID prefix, or the `^spiffe://...` regular
+	// expression, that identifies the workloads that can store secrets.
+	// Env: VSECM_SPIFFEID_PREFIX_CLERK
+	ClerkIDPrefix string
+
+	// SentinelIDPrefix is the SPIFFE ID prefix, or the `^spiffe://...`
+	// regular expression, that identifies VSecM Sentinel.
+	// Env: VSECM_SPIFFEID_PREFIX_SENTINEL
+	SentinelIDPrefix string
+
+	// ScoutIDPrefix is the SPIFFE ID prefix, or the `^spiffe://...` regular
+	// expression, that identifies VSecM Scout.
+	// Env: VSECM_SPIFFEID_PREFIX_SCOUT
+	ScoutIDPrefix string
+
+	// Roles are the user-defined roles, along with their SPIFFE ID prefixes
+	// or `^spiffe://...` regular expressions. A workload has a user-defined
+	// role if its SPIFFE ID matches both the role and WorkloadIDPrefix.
+	Roles map[Role]string
+
+	// Grants tell which roles may perform an Operation, replacing the
+	// defaults for that Operation: RoleWorkload may fetch, RoleClerk may
+	// store, and RoleClerk and RoleSentinel may upsert, delete, and list.
+	Grants map[Operation][]Role
+
+	// WorkloadIDPrefix is the SPIFFE ID prefix, or the `^spiffe://...`
+	// regular expression, that identifies the workloads known to VSecM.
+	// Env: VSECM_SPIFFEID_PREFIX_WORKLOAD
+	WorkloadIDPrefix string
+
+	// WorkloadNameRegExp is the regular expression that extracts the
+	// workload name from the workload's SPIFFE ID.
+	// Env: VSECM_WORKLOAD_NAME_REGEXP
+	WorkloadNameRegExp string
+
+	// PollInterval is the interval between two polls of the Watch loop. It
+	// shall be positive.
+	// Env: VSECM_SIDECAR_POLL_INTERVAL
+	PollInterval time.Duration
+
+	// SecretsPath is the file that the Watch loop saves the secret into.
+	// Env: VSECM_SIDECAR_SECRETS_PATH
+	SecretsPath string
+
+	// SecretsFileMode is the permissions of the secrets file.
+	// Env: VSECM_SIDECAR_SECRETS_FILE_MODE (octal, defaults to 0600)
+	SecretsFileMode os.FileMode
+
+	// SecretsFileUID and SecretsFileGID are the user and the group that own
+	// the secrets file. A negative value leaves the owner as is.
+	// Env: VSECM_SIDECAR_SECRETS_FILE_UID, VSECM_SIDECAR_SECRETS_FILE_GID
+	SecretsFileUID int
+	SecretsFileGID int
+
+	// Sinks are the outputs of the Watch loop. If there are none, the
+	// secret is written as is to SecretsPath. See ParseSinks for the format
+	// of the environment variables.
+	// Env: VSECM_SIDECAR_OUTPUTS, or VSECM_SIDECAR_OUTPUTS_FILE
+	Sinks []Sink
+
+	// Hooks are run by the Watch loop after the secret has changed and has
+	// been written. See ParseHooks for the format of the environment
+	// variables.
+	// Env: VSECM_SIDECAR_HOOKS, or VSECM_SIDECAR_HOOKS_FILE
+	Hooks []Hook
+
+	// DeletePolicy is what the Watch loop does to its outputs when the
+	// secret has been deleted from VSecM Safe. Defaults to DeleteWipe.
+	// Env: VSECM_SIDECAR_DELETE_POLICY (wipe, keep, grace, or delete)
+	DeletePolicy DeletePolicy
+
+	// DeleteGracePeriod is how long DeleteGrace keeps a deleted secret.
+	// Env: VSECM_SIDECAR_DELETE_GRACE_PERIOD (in milliseconds)
+	DeleteGracePeriod time.Duration
+
+	// TombstonePath is the file that the Watch loop creates when a secret
+	// that it has delivered, or that its outputs hold from a previous run,
+	// is deleted, and removes when the secret comes back; whatever the
+	// DeletePolicy is. The file holds the time of the
+	// deletion. No file is created if TombstonePath is empty.
+	// Env: VSECM_SIDECAR_TOMBSTONE_PATH
+	TombstonePath string
+
+	// HealthBindAddr is the address that the Watch loop serves its health
+	// endpoints on. No server is started if HealthBindAddr is empty.
+	// Env: VSECM_SIDECAR_HEALTH_BIND_ADDR
+	HealthBindAddr string
+
+	// StalePolls is the number of poll intervals without a successful poll
+	// after which the Watch loop is not ready anymore. Defaults to 3.
+	// Env: VSECM_SIDECAR_STALE_POLLS
+	StalePolls int
+
+	// sinksErr and hooksErr are the errors of reading Sinks and Hooks from
+	// the environment. They are reported when the Watch loop starts, unless
+	// Sinks or Hooks have been set since.
+	sinksErr error
+	hooksErr error
+}
+
+// DefaultConfig returns the Config built from the environment variables,
+// falling back to the VSecM defaults for the variables that are not set.
+func DefaultConfig() Config {
+	uid, gid := env.SecretsFileOwnerForSidecar()
+	sinks, sinksErr := sinksFromEnv()
+	hooks, hooksErr := hooksFromEnv()
+
+	return Config{
+		SafeEndpoint:       env.EndpointUrlForSafe(),
+		SpiffeSocket:       env.SpiffeSocketUrl(),
+		TrustDomain:        env.SpiffeTrustDomain(),
+		SafeIDPrefix:       env.SpiffeIdPrefixForSafe(),
+		ClerkIDPrefix:      env.SpiffeIdPrefixForClerk(),
+		SentinelIDPrefix:   env.SpiffeIdPrefixForSentinel(),
+		ScoutIDPrefix:      env.SpiffeIdPrefixForScout(),
+		WorkloadIDPrefix:   env.SpiffeIdPrefixForWorkload(),
+		WorkloadNameRegExp: env.NameRegExpForWorkload(),
+		PollInterval:       env.PollIntervalForSidecar(),
+		SecretsPath:        env.SecretsPathForSidecar(),
+		SecretsFileMode:    env.SecretsFileModeForSidecar(),
+		SecretsFileUID:     uid,
+		SecretsFileGID:     gid,
+		DeletePolicy:       DeletePolicy(env.DeletePolicyForSidecar()),
+		DeleteGracePeriod:  env.DeleteGracePeriodForSidecar(),
+		TombstonePath:      env.TombstonePathForSidecar(),
+		HealthBindAddr:     env.HealthBindAddrForSidecar(),
+		StalePolls:         env.StalePollsForSidecar(),
+		Sinks:              sinks,
+		Hooks:              hooks,
+		sinksErr:           sinksErr,
+		hooksErr:           hooksErr,
+	}
+}
+
+// check returns an error that wraps ErrInvalidConfig if a setting of the
+// Config that is not a SPIFFE ID pattern is invalid.
+func (c Config) check(scope string) error {
+	if c.PollInterval <= 0 {
+		return errors.Join(
+			fmt.Errorf("invalid poll interval: %s", c.PollInterval),
+			fmt.Errorf("%s: %w", scope, ErrInvalidConfig),
+		)
+	}
+
+	return nil
+}
+
+// rules returns the SPIFFE ID validation rules described by the Config.
+func (c Config) rules() validation.Rules {
+	return validation.Rules{
+		TrustDomain:        c.TrustDomain,
+		WorkloadPrefix:     c.WorkloadIDPrefix,
+		WorkloadNameRegExp: c.WorkloadNameRegExp,
+		SafePrefix:         c.SafeIDPrefix,
+		ClerkPrefix:        c.ClerkIDPrefix,
+		SentinelPrefix:     c.SentinelIDPrefix,
+		ScoutPrefix:        c.ScoutIDPrefix,
+		Roles:              c.Roles,
+	}
+}
+
+// Option overrides a setting of the Config that a Client is created with.
+type Option func(*Config)
+
+// WithConfig replaces the whole Config. Options that come after it are
+// applied on top of it.
+func WithConfig(cfg Config) Option {
+	return func(c *Config) {
+		*c = cfg
+	}
+}
+
+// WithSafeEndpoint sets the base URL of the VSecM Safe API.
+func WithSafeEndpoint(u string) Option {
+	return func(c *Config) {
+		c.SafeEndpoint = u
+	}
+}
+
+// WithSpiffeSocket sets the SPIFFE Workload API socket address that the
+// Client connects to (e.g. "unix:///spire-agent-socket/spire-agent.sock").
+func WithSpiffeSocket(addr string) Option {
+	return func(c *Config) {
+		c.SpiffeSocket = addr
+	}
+}
+
+// WithTrustDomain sets the SPIFFE trust domain of VSecM.
+//
+// The SPIFFE ID patterns, and the WorkloadNameRegExp, that are anchored to
+// the previous trust domain, such as the defaults, are moved to td as well;
+// otherwise, they would never match. Patterns set by Options that come after
+// WithTrustDomain are kept as they are.
+func WithTrustDomain(td string) Option {
+	return func(c *Config) {
+		from := c.TrustDomain
+		c.TrustDomain = td
+
+		if from == "" || from == td {
+			return
+		}
+		for _, p := range []*string{
+			&c.SafeIDPrefix, &c.ClerkIDPrefix, &c.SentinelIDPrefix,
+			&c.ScoutIDPrefix, &c.WorkloadIDPrefix, &c.WorkloadNameRegExp,
+		} {
+			*p = rebase(*p, from, td)
+		}
+
+		if len(c.Roles) == 0 {
+			return
+		}
+		roles := make(map[Role]string, len(c.Roles))
+		for r, p := range c.Roles {
+			roles[r] = rebase(p, from, td)
+		}
+		c.Roles = roles
+	}
+}
+
+// rebase moves pattern from the trust domain from to the trust domain to, if
+// it is anchored to from; either as a plain prefix, or as a regular
+// expression.
+func rebase(pattern, from, to string) string {
+	for _, anchor := range []string{"^spiffe://", "spiffe://"} {
+		if rest, ok := strings.CutPrefix(pattern, anchor+from+"/"); ok {
+			return anchor + to + "/" + rest
+		}
+	}
+	return pattern
+}
+
+// WithSafeIDMatcher sets the SPIFFE ID prefix, or the `^spiffe://...`
+// regular expression, that VSecM Safe is expected to present.
+func WithSafeIDMatcher(pattern string) Option {
+	return func(c *Config) {
+		c.SafeIDPrefix = pattern
+	}
+}
+
+// WithClerkIDMatcher sets the SPIFFE ID prefix, or the `^spiffe://...`
+// regular expression, that identifies the workloads that can store secrets.
+func WithClerkIDMatcher(pattern string) Option {
+	return func(c *Config) {
+		c.ClerkIDPrefix = pattern
+	}
+}
+
+// WithSentinelIDMatcher sets the SPIFFE ID prefix, or the `^spiffe://...`
+// regular expression, that identifies VSecM Sentinel.
+func WithSentinelIDMatcher(pattern string) Option {
+	return func(c *Config) {
+		c.SentinelIDPrefix = pattern
+	}
+}
+
+// WithScoutIDMatcher sets the SPIFFE ID prefix, or the `^spiffe://...`
+// regular expression, that identifies VSecM Scout.
+func WithScoutIDMatcher(pattern string) Option {
+	return func(c *Config) {
+		c.ScoutIDPrefix = pattern
+	}
+}
+
+// WithRole registers a user-defined role, along with the SPIFFE ID prefix, or
+// the `^spiffe://...` regular expression, that identifies it.
+func WithRole(role Role, pattern string) Option {
+	return func(c *Config) {
+		roles := make(map[Role]string, len(c.Roles)+1)
+		for r, p := range c.Roles {
+			roles[r] = p
+		}
+		roles[role] = pattern
+		c.Roles = roles
+	}
+}
+
+// WithGrant sets the roles that may perform op, replacing the default ones.
+//
+//	client, err := sentry.New(ctx,
+//	    sentry.WithRole("operator", "^spiffe://vsecm.com/workload/operator/.*$"),
+//	    sentry.WithGrant(sentry.OpList, sentry.RoleSentinel, "operator"),
+//	)
+func WithGrant(op Operation, roles ...Role) Option {
+	return func(c *Config) {
+		grants := make(map[Operation][]Role, len(c.Grants)+1)
+		for o, r := range c.Grants {
+			grants[o] = r
+		}
+		grants[op] = roles
+		c.Grants = grants
+	}
+}
+
+// WithWorkloadIDMatcher sets the SPIFFE ID prefix, or the `^spiffe://...`
+// regular expression, that identifies the workloads known to VSecM.
+func WithWorkloadIDMatcher(pattern string) Option {
+	return func(c *Config) {
+		c.WorkloadIDPrefix = pattern
+	}
+}
+
+// WithWorkloadNameRegExp sets the regular expression that extracts the
+// workload name from the workload's SPIFFE ID.
+func WithWorkloadNameRegExp(pattern string) Option {
+	return func(c *Config) {
+		c.WorkloadNameRegExp = pattern
+	}
+}
+
+// WithPollInterval sets the interval between two polls of the Watch loop.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.PollInterval = d
+	}
+}
+
+// WithSecretsPath sets the file that the Watch loop saves the secret into,
+// replacing the Sinks.
+func WithSecretsPath(path string) Option {
+	return func(c *Config) {
+		c.SecretsPath = path
+		c.Sinks = nil
+		c.sinksErr = nil
+	}
+}
+
+// WithSecretsFileMode sets the permissions of the secrets file.
+func WithSecretsFileMode(mode os.FileMode) Option {
+	return func(c *Config) {
+		c.SecretsFileMode = mode
+	}
+}
+
+// WithSecretsFileOwner sets the user and the group that own the secrets
+// file. A negative value leaves the corresponding owner as is.
+func WithSecretsFileOwner(uid, gid int) Option {
+	return func(c *Config) {
+		c.SecretsFileUID = uid
+		c.SecretsFileGID = gid
+	}
+}
+
+// WithSinks sets the outputs of the Watch loop, replacing the SecretsPath.
+func WithSinks(sinks ...Sink) Option {
+	return func(c *Config) {
+		c.Sinks = sinks
+		c.sinksErr = nil
+	}
+}
+
+// WithHooks sets the hooks that the Watch loop runs after the secret changes.
+// Calling it with no hooks turns the hooks off.
+func WithHooks(hooks ...Hook) Option {
+	return func(c *Config) {
+		c.Hooks = hooks
+		c.hooksErr = nil
+	}
+}
+
+// WithDeletePolicy sets what the Watch loop does when the secret has been
+// deleted. grace is only used by DeleteGrace.
+func WithDeletePolicy(p DeletePolicy, grace time.Duration) Option {
+	return func(c *Config) {
+		c.DeletePolicy = p
+		c.DeleteGracePeriod = grace
+	}
+}
+
+// WithTombstonePath sets the file that the Watch loop creates when the
+// secret has been deleted.
+func WithTombstonePath(path string) Option {
+	return func(c *Config) {
+		c.TombstonePath = path
+	}
+}
+
+// WithHealthServer sets the address that the Watch loop serves its health
+// endpoints on, and the number of poll intervals without a successful poll
+// after which it is not ready anymore.
+func WithHealthServer(addr string, stalePolls int) Option {
+	return func(c *Config) {
+		c.HealthBindAddr = addr
+		c.StalePolls = stalePolls
+	}
+}
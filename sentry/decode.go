@@ -0,0 +1,119 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	tpl "github.com/spiffe/vsecm-sdk-go/internal/core/template"
+)
+
+// Decode decodes the value of a secret into v, which shall be a non-nil
+// pointer.
+//
+// If v is a *[]byte or a *string, it receives the value as is. Otherwise,
+// the value is decoded according to format:
+//   - FormatJSON: the value is decoded as JSON.
+//   - FormatYAML: the value is decoded as YAML. The `json` struct tags of v
+//     are honored, so the same struct can hold both JSON and YAML secrets.
+//   - FormatRaw and FormatAuto: the format is detected from the value; JSON
+//     is tried first, and YAML next.
+//
+// Decode returns an error that wraps ErrInvalidSecret if the value cannot be
+// decoded into v.
+func Decode(value string, format SecretFormat, v any) error {
+	switch t := v.(type) {
+	case *[]byte:
+		*t = []byte(value)
+		return nil
+	case *string:
+		*t = value
+		return nil
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("decode: %w: the secret is empty", ErrInvalidSecret)
+	}
+
+	switch format {
+	case FormatJSON:
+		return decodeJSON(value, v)
+	case FormatYAML:
+		return decodeYAML(value, v)
+	case FormatRaw, FormatAuto:
+		if json.Valid([]byte(value)) {
+			return decodeJSON(value, v)
+		}
+		return decodeYAML(value, v)
+	default:
+		return fmt.Errorf("decode: %w: unknown format: %s",
+			ErrInvalidSecret, format)
+	}
+}
+
+func decodeJSON(value string, v any) error {
+	err := json.Unmarshal([]byte(value), v)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("decode: %w", ErrInvalidSecret))
+	}
+	return nil
+}
+
+func decodeYAML(value string, v any) error {
+	js, err := tpl.YamlToJson(value)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("decode: %w", ErrInvalidSecret))
+	}
+	return decodeJSON(js, v)
+}
+
+// FetchInto fetches the secret of the workload, and decodes its value into
+// v. See Decode for how format and v are interpreted.
+//
+//	var db struct {
+//	    Username string `json:"username"`
+//	    Password string `json:"password"`
+//	}
+//	err := client.FetchInto(ctx, &db, sentry.FormatAuto)
+func (c *Client) FetchInto(ctx context.Context, v any, format SecretFormat) error {
+	r, err := c.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return Decode(r.Data, format, v)
+}
+
+// FetchAs fetches the secret of the workload through c, and decodes its value
+// into a T. See Decode for how format and T are interpreted. If c is nil, the
+// package-level Client is used.
+//
+//	db, err := sentry.FetchAs[DatabaseConfig](ctx, client, sentry.FormatYAML)
+//	values, err := sentry.FetchAs[map[string]string](ctx, nil, sentry.FormatAuto)
+func FetchAs[T any](ctx context.Context, c *Client, format SecretFormat) (T, error) {
+	var v T
+
+	if c == nil {
+		dc, err := getDefaultClient(ctx)
+		if err != nil {
+			return v, err
+		}
+		c = dc
+	}
+
+	err := c.FetchInto(ctx, &v, format)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
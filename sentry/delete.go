@@ -0,0 +1,58 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// Delete removes the secrets of the given workloads from VSecM Safe.
+//
+//	err := client.Delete(ctx, "example")
+//	if errors.Is(err, sentry.ErrSecretNotFound) {
+//	    // Nothing to delete.
+//	}
+//
+// Delete returns an error that matches ErrSecretNotFound if VSecM Safe has no
+// secret for the workloads, and an error that matches ErrSafeUnauthorized if
+// VSecM Safe refuses to delete them.
+//
+// Like Upsert, Delete is only available to privileged workloads; that is,
+// clerk workloads and VSecM Sentinel, unless the Grants of the Config tell
+// otherwise. Calling it from any other workload returns an error that wraps
+// ErrUntrustedWorkload, without contacting VSecM Safe.
+func (c *Client) Delete(ctx context.Context, workloadIDs ...string) error {
+	id, err := c.identify(OpDelete)
+	if err != nil {
+		return err
+	}
+
+	if len(workloadIDs) == 0 {
+		return fmt.Errorf(
+			"delete: %w: at least one workload is required", ErrInvalidRequest,
+		)
+	}
+
+	debug.Log("Sentry:Delete svid:id: ", id)
+
+	sr := &api.SecretDeleteRequest{
+		WorkloadIds: workloadIDs,
+	}
+
+	code, body, err := c.send(
+		ctx, "delete", http.MethodDelete, "/sentinel/v1/secrets", sr,
+	)
+	if err != nil {
+		return err
+	}
+
+	return receive("delete", code, body, nil)
+}
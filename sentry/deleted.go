@@ -0,0 +1,137 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+	"github.com/spiffe/vsecm-sdk-go/internal/lib/file"
+)
+
+// DeletePolicy tells what the Watch loop does to its outputs when the secret
+// of the workload has been deleted from VSecM Safe.
+type DeletePolicy string
+
+const (
+	// DeleteWipe empties the outputs right away. This is the default.
+	DeleteWipe DeletePolicy = "wipe"
+	// DeleteKeep keeps the last known secret in the outputs.
+	DeleteKeep DeletePolicy = "keep"
+	// DeleteGrace keeps the last known secret for DeleteGracePeriod, and
+	// then empties the outputs, unless the secret comes back in the
+	// meantime.
+	DeleteGrace DeletePolicy = "grace"
+	// DeleteRemove removes the output files altogether.
+	DeleteRemove DeletePolicy = "delete"
+)
+
+// deletePolicy returns the DeletePolicy of the Config, checked.
+func (c Config) deletePolicy() (DeletePolicy, error) {
+	switch c.DeletePolicy {
+	case "":
+		return DeleteWipe, nil
+	case DeleteWipe, DeleteKeep, DeleteGrace, DeleteRemove:
+		return c.DeletePolicy, nil
+	default:
+		return "", fmt.Errorf("unknown delete policy: %s", c.DeletePolicy)
+	}
+}
+
+// deletion is what a Watch loop knows about the deletion of its secret.
+type deletion struct {
+	policy    DeletePolicy
+	grace     time.Duration
+	tombstone string
+
+	// delivered tells whether a secret has been written during this run.
+	// The tombstone is written only if the application may have a secret;
+	// that is, if it has been delivered, or if the outputs hold one.
+	delivered bool
+	// since is when the secret has been found deleted; zero if it exists.
+	since time.Time
+}
+
+// deleted applies the DeletePolicy of the sidecar to a secret that VSecM Safe
+// does not have anymore.
+func (s *sidecar) deleted(ctx context.Context) error {
+	d := &s.deletion
+
+	if d.since.IsZero() {
+		d.since = time.Now()
+		debug.Log("Sentry: secret has been deleted; policy:", string(d.policy))
+
+		if d.delivered || s.sinks.populated() {
+			s.markDeleted()
+		}
+	}
+
+	switch d.policy {
+	case DeleteKeep:
+		return nil
+	case DeleteGrace:
+		if time.Since(d.since) < d.grace {
+			return nil
+		}
+		return s.save(ctx, "")
+	case DeleteRemove:
+		removed, err := s.sinks.remove()
+		if err != nil {
+			return err
+		}
+		if removed {
+			s.status.wrote()
+			runHooks(ctx, s.hooks)
+		}
+		return nil
+	default:
+		return s.save(ctx, "")
+	}
+}
+
+// restored forgets about a previous deletion, once the secret is back. The
+// first secret of a run removes the tombstone too, since a previous run may
+// have left it behind.
+func (s *sidecar) restored() {
+	d := &s.deletion
+	first := !d.delivered
+	d.delivered = true
+
+	if d.since.IsZero() && !first {
+		return
+	}
+	d.since = time.Time{}
+
+	if d.tombstone == "" {
+		return
+	}
+	err := os.Remove(d.tombstone)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		debug.Log("Sentry: problem removing tombstone: ", err.Error())
+	}
+}
+
+// markDeleted writes the tombstone file, if any, so that the application can
+// tell that its secret has been revoked. The file holds the time of the
+// deletion.
+func (s *sidecar) markDeleted() {
+	d := &s.deletion
+	if d.tombstone == "" {
+		return
+	}
+
+	err := file.WriteAtomic(
+		d.tombstone, []byte(d.since.UTC().Format(time.RFC3339)+"\n"),
+		s.sinks.mode, s.sinks.owner,
+	)
+	if err != nil {
+		debug.Log("Sentry: problem writing tombstone: ", err.Error())
+	}
+}
@@ -0,0 +1,124 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// The errors below classify every failure that the SDK can return.
+// The returned errors wrap them, so match them with errors.Is:
+//
+//	_, err := sentry.Fetch()
+//	if errors.Is(err, sentry.ErrSVIDUnavailable) {
+//	    // The SPIFFE Workload API has not given us an identity (yet).
+//	}
+var (
+	// ErrSecretNotFound is returned when the secret is not found.
+	ErrSecretNotFound = errors.New("secret does not exist")
+
+	// ErrSVIDUnavailable is returned when the workload's X.509 SVID cannot
+	// be obtained from the SPIFFE Workload API.
+	ErrSVIDUnavailable = errors.New("SVID is not available")
+
+	// ErrUntrustedWorkload is returned when the SPIFFE ID of the calling
+	// workload is not allowed to perform the operation.
+	ErrUntrustedWorkload = errors.New("untrusted workload")
+
+	// ErrUntrustedSafe is returned when the server does not present the
+	// SPIFFE ID of VSecM Safe during the mTLS handshake.
+	ErrUntrustedSafe = errors.New("untrusted VSecM Safe")
+
+	// ErrSafeUnreachable is returned when VSecM Safe cannot be reached, or
+	// when the connection fails before a complete response is received.
+	ErrSafeUnreachable = errors.New(
+		"problem connecting to VSecM Safe API endpoint",
+	)
+
+	// ErrSafeUnauthorized is returned when VSecM Safe refuses the request
+	// of the workload (HTTP 401 or 403).
+	ErrSafeUnauthorized = errors.New("VSecM Safe refused the workload")
+
+	// ErrInvalidConfig is returned by New when the Config is invalid; for
+	// example, because a SPIFFE ID pattern does not compile.
+	ErrInvalidConfig = errors.New("invalid configuration")
+
+	// ErrInvalidRequest is returned when the request to VSecM Safe cannot be
+	// generated; for example, because the Safe endpoint URL is malformed.
+	ErrInvalidRequest = errors.New("problem generating the request")
+
+	// ErrInvalidSecret is returned when the value of a secret cannot be
+	// decoded into the type that the caller has asked for.
+	ErrInvalidSecret = errors.New("unable to decode secret")
+
+	// ErrInvalidResponse is returned when the response of VSecM Safe cannot
+	// be deserialized.
+	ErrInvalidResponse = errors.New("unable to deserialize response")
+
+	// ErrWatchStopped is returned by the Watch loop once it stops. The
+	// returned error also wraps the reason: context.Canceled,
+	// context.DeadlineExceeded, the cause of the context, or a SignalError.
+	ErrWatchStopped = errors.New("watch stopped")
+)
+
+// HTTPStatusError is returned when VSecM Safe responds with an unexpected
+// HTTP status code.
+//
+// HTTPStatusError matches ErrSafeUnauthorized for 401 and 403, and
+// ErrSecretNotFound for 404, with errors.Is.
+type HTTPStatusError struct {
+	// Code is the HTTP status code of the response.
+	Code int
+	// Body is the body of the response, if any.
+	Body string
+}
+
+func (e *HTTPStatusError) Error() string {
+	msg := fmt.Sprintf("unexpected response from VSecM Safe: %d %s",
+		e.Code, http.StatusText(e.Code))
+	if e.Body == "" {
+		return msg
+	}
+	return msg + ": " + e.Body
+}
+
+// Is reports whether the status code of e falls into the class of target.
+func (e *HTTPStatusError) Is(target error) bool {
+	switch target {
+	case ErrSafeUnauthorized:
+		return e.Code == http.StatusUnauthorized ||
+			e.Code == http.StatusForbidden
+	case ErrSecretNotFound:
+		return e.Code == http.StatusNotFound
+	default:
+		return false
+	}
+}
+
+// SignalError is the reason of a Watch loop that has been stopped by a
+// signal, such as SIGTERM.
+type SignalError struct {
+	// Signal is the signal that has been received.
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "received signal: " + e.Signal.String()
+}
+
+// ServerError is returned when VSecM Safe reports a failure in the `err`
+// field of its response.
+type ServerError struct {
+	// Err is the error message that VSecM Safe has sent.
+	Err string
+}
+
+func (e *ServerError) Error() string {
+	return "VSecM Safe: " + e.Err
+}
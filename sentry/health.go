@@ -0,0 +1,94 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultStalePolls is the number of poll intervals without a successful
+// poll after which the Watch loop is not ready anymore.
+const defaultStalePolls = 3
+
+// watchHealth exposes the WatchStatus of a Watch loop to the health server.
+type watchHealth struct {
+	status *watchStatus
+	stale  time.Duration
+}
+
+func newWatchHealth(status *watchStatus, cfg Config) watchHealth {
+	polls := cfg.StalePolls
+	if polls <= 0 {
+		polls = defaultStalePolls
+	}
+
+	return watchHealth{
+		status: status,
+		stale:  time.Duration(polls) * cfg.PollInterval,
+	}
+}
+
+func (h watchHealth) Live() error {
+	if !h.status.get().Running {
+		return errors.New("watch loop is not running")
+	}
+	return nil
+}
+
+func (h watchHealth) Ready() error {
+	s := h.status.get()
+
+	if s.LastWrite.IsZero() {
+		return errors.New("secret has not been written yet")
+	}
+
+	if since := time.Since(s.LastSuccess); since > h.stale {
+		return fmt.Errorf(
+			"last successful poll was %s ago; %d consecutive failures",
+			since.Round(time.Second), s.ConsecutiveFailures,
+		)
+	}
+
+	return nil
+}
+
+// watchStatusJSON is the WatchStatus as /status renders it.
+type watchStatusJSON struct {
+	Running             bool       `json:"running"`
+	LastAttempt         *time.Time `json:"lastAttempt,omitempty"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastWrite           *time.Time `json:"lastWrite,omitempty"`
+	LastUpdated         string     `json:"lastUpdated,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+func (h watchHealth) Status() any {
+	s := h.status.get()
+
+	j := watchStatusJSON{
+		Running:             s.Running,
+		LastAttempt:         timeOrNil(s.LastAttempt),
+		LastSuccess:         timeOrNil(s.LastSuccess),
+		LastWrite:           timeOrNil(s.LastWrite),
+		LastUpdated:         s.LastUpdated,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+	}
+	if s.LastError != nil {
+		j.LastError = s.LastError.Error()
+	}
+
+	return j
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
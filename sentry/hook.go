@@ -0,0 +1,324 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
+	"github.com/spiffe/vsecm-sdk-go/internal/lib/backoff"
+)
+
+// Hook is an action that the Watch loop takes after the secret has changed
+// and has been written to its Sinks, so that the application can reload it.
+//
+// A Hook does exactly one of the following:
+//   - runs Command;
+//   - sends Signal to the process whose PID is in PIDFile;
+//   - sends Signal to the processes named ProcessName; this requires a PID
+//     namespace that is shared between the containers of the Pod;
+//   - sends an HTTP request to URL.
+//
+// Failed hooks are logged and retried; they never stop the Watch loop.
+type Hook struct {
+	// Command is the command to run, along with its arguments.
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// Signal is the signal to send; e.g. "SIGHUP", or a signal number.
+	// Defaults to SIGHUP.
+	Signal string `json:"signal,omitempty" yaml:"signal,omitempty"`
+	// PIDFile is the file that holds the PID to send Signal to.
+	PIDFile string `json:"pidFile,omitempty" yaml:"pidFile,omitempty"`
+	// ProcessName is the name of the processes to send Signal to; that is,
+	// the base name of their executable, as in their command line, or the
+	// name that the kernel shows for them, which is at most 15 characters
+	// long.
+	ProcessName string `json:"processName,omitempty" yaml:"processName,omitempty"`
+
+	// URL is the local reload endpoint to call.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Method is the HTTP method to call URL with. Defaults to POST.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+
+	// Timeout bounds a single attempt of the hook. Defaults to 10 seconds.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+const defaultHookTimeout = 10 * time.Second
+
+// signals are the signals that a Hook can send by name.
+var signals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// ParseHooks parses a list of Hooks from YAML or JSON, such as:
+//
+//   - command: ["nginx", "-s", "reload"]
+//     timeout: 5s
+//   - pidFile: /run/app/app.pid
+//     signal: SIGHUP
+//   - url: http://127.0.0.1:8080/-/reload
+func ParseHooks(data []byte) ([]Hook, error) {
+	var hooks []Hook
+	err := yaml.Unmarshal(data, &hooks)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("unable to parse hooks"))
+	}
+
+	return validateHooks(hooks)
+}
+
+// validateHooks checks hooks.
+func validateHooks(hooks []Hook) ([]Hook, error) {
+	for i, h := range hooks {
+		n := 0
+		if len(h.Command) > 0 {
+			n++
+		}
+		if h.PIDFile != "" {
+			n++
+		}
+		if h.ProcessName != "" {
+			n++
+		}
+		if h.URL != "" {
+			n++
+		}
+		if n != 1 {
+			return nil, fmt.Errorf(
+				"hook %d: exactly one of command, pidFile, processName, "+
+					"or url is required", i,
+			)
+		}
+
+		if _, err := h.signal(); err != nil {
+			return nil, fmt.Errorf("hook %d: %w", i, err)
+		}
+	}
+
+	return hooks, nil
+}
+
+// hooksFromEnv reads the Hooks from the VSECM_SIDECAR_HOOKS environment
+// variable, or from the file that VSECM_SIDECAR_HOOKS_FILE points to. It
+// returns no Hooks if neither is set.
+func hooksFromEnv() ([]Hook, error) {
+	if h := env.HooksForSidecar(); h != "" {
+		return ParseHooks([]byte(h))
+	}
+
+	if p := env.HooksFileForSidecar(); p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("unable to read hooks"))
+		}
+		return ParseHooks(b)
+	}
+
+	return nil, nil
+}
+
+// hooks returns the Hooks of the Config, checked.
+func (c Config) hooks() ([]Hook, error) {
+	if len(c.Hooks) > 0 {
+		return validateHooks(c.Hooks)
+	}
+
+	return nil, c.hooksErr
+}
+
+func (h Hook) String() string {
+	switch {
+	case len(h.Command) > 0:
+		return "command " + strings.Join(h.Command, " ")
+	case h.PIDFile != "":
+		return "signal to pid file " + h.PIDFile
+	case h.ProcessName != "":
+		return "signal to process " + h.ProcessName
+	default:
+		return "url " + h.URL
+	}
+}
+
+func (h Hook) signal() (syscall.Signal, error) {
+	if h.Signal == "" {
+		return syscall.SIGHUP, nil
+	}
+
+	name := strings.ToUpper(h.Signal)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if s, ok := signals[name]; ok {
+		return s, nil
+	}
+
+	n, err := strconv.Atoi(h.Signal)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("unknown signal: %s", h.Signal)
+	}
+	return syscall.Signal(n), nil
+}
+
+// run runs the hook once.
+func (h Hook) run(ctx context.Context) error {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case len(h.Command) > 0:
+		out, err := exec.CommandContext(
+			ctx, h.Command[0], h.Command[1:]...,
+		).CombinedOutput()
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("output: %s", out))
+		}
+		return nil
+	case h.PIDFile != "":
+		b, err := os.ReadFile(h.PIDFile)
+		if err != nil {
+			return err
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+		if err != nil {
+			return errors.Join(err, errors.New("invalid pid file"))
+		}
+		return h.kill(pid)
+	case h.ProcessName != "":
+		pids, err := findProcesses(h.ProcessName)
+		if err != nil {
+			return err
+		}
+		if len(pids) == 0 {
+			return fmt.Errorf("no process named %s", h.ProcessName)
+		}
+		var errs []error
+		for _, pid := range pids {
+			errs = append(errs, h.kill(pid))
+		}
+		return errors.Join(errs...)
+	default:
+		method := h.Method
+		if method == "" {
+			method = http.MethodPost
+		}
+		req, err := http.NewRequestWithContext(ctx, method, h.URL, nil)
+		if err != nil {
+			return err
+		}
+		r, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = r.Body.Close()
+		if r.StatusCode < http.StatusOK ||
+			r.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("unexpected status: %s", r.Status)
+		}
+		return nil
+	}
+}
+
+func (h Hook) kill(pid int) error {
+	sig, err := h.signal()
+	if err != nil {
+		return err
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return p.Signal(sig)
+}
+
+// findProcesses returns the PIDs of the processes named name, except for the
+// current one. It relies on /proc; so it only works on Linux.
+//
+// The name is matched against the base name of the first argument of the
+// command line of each process, since /proc/<pid>/comm is truncated to 15
+// characters; and against /proc/<pid>/comm, for the processes that have
+// renamed themselves, or that have no command line.
+func findProcesses(name string) ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, errors.Join(err, errors.New("unable to list processes"))
+	}
+
+	self := os.Getpid()
+
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == self {
+			continue
+		}
+
+		if processNamed(e.Name(), name) {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+// processNamed tells whether the process with the given /proc entry is
+// named name.
+func processNamed(entry, name string) bool {
+	cmdline, err := os.ReadFile(filepath.Join("/proc", entry, "cmdline"))
+	if err == nil {
+		argv0, _, _ := strings.Cut(string(cmdline), "\x00")
+		if argv0 != "" && filepath.Base(argv0) == name {
+			return true
+		}
+	}
+
+	comm, err := os.ReadFile(filepath.Join("/proc", entry, "comm"))
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(comm)) == name
+}
+
+// runHooks runs every hook, retrying the ones that fail. Failures are logged
+// and do not stop the other hooks.
+func runHooks(ctx context.Context, hooks []Hook) {
+	for _, h := range hooks {
+		err := backoff.RetryContext(ctx, "sentry.hook", func() error {
+			return h.run(ctx)
+		}, backoff.Strategy{
+			MaxRetries:  3,
+			Delay:       time.Second,
+			Exponential: true,
+		})
+		if err != nil {
+			logWarning("hook failed:", h.String(), err.Error())
+		}
+	}
+}
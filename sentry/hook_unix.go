@@ -0,0 +1,16 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+//go:build unix
+
+package sentry
+
+import "syscall"
+
+func init() {
+	// These signals only exist on Unix-like systems.
+	signals["SIGUSR1"] = syscall.SIGUSR1
+	signals["SIGUSR2"] = syscall.SIGUSR2
+}
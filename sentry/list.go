@@ -0,0 +1,147 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// SecretInfo is the metadata of a secret stored in VSecM Safe.
+// It does not contain the value of the secret.
+type SecretInfo struct {
+	Name         string
+	Created      time.Time
+	Updated      time.Time
+	NotBefore    time.Time
+	ExpiresAfter time.Time
+}
+
+// EncryptedSecret is a secret stored in VSecM Safe, along with its value
+// encrypted by VSecM Safe. It is safe to keep as a backup.
+type EncryptedSecret struct {
+	SecretInfo
+	EncryptedValue string
+}
+
+// EncryptedSecretList is the list of the encrypted secrets in VSecM Safe.
+type EncryptedSecretList struct {
+	// Algorithm is the algorithm that the values are encrypted with.
+	Algorithm string
+	Secrets   []EncryptedSecret
+}
+
+// newSecretInfo builds the SecretInfo that List and ListEncrypted return out
+// of the fields of a secret that VSecM Safe has sent.
+func newSecretInfo(
+	name string, created, updated, notBefore, expiresAfter api.JsonTime,
+) SecretInfo {
+	return SecretInfo{
+		Name:         name,
+		Created:      time.Time(created),
+		Updated:      time.Time(updated),
+		NotBefore:    time.Time(notBefore),
+		ExpiresAfter: time.Time(expiresAfter),
+	}
+}
+
+func secretInfo(s api.Secret) SecretInfo {
+	return newSecretInfo(
+		s.Name, s.Created, s.Updated, s.NotBefore, s.ExpiresAfter,
+	)
+}
+
+func encryptedSecret(s api.SecretEncrypted) EncryptedSecret {
+	return EncryptedSecret{
+		SecretInfo: newSecretInfo(
+			s.Name, s.Created, s.Updated, s.NotBefore, s.ExpiresAfter,
+		),
+		EncryptedValue: s.EncryptedValue,
+	}
+}
+
+// List returns the metadata of every secret in VSecM Safe. The values of the
+// secrets are not included; use ListEncrypted for that.
+//
+//	secrets, err := client.List(ctx)
+//	for _, s := range secrets {
+//	    fmt.Println(s.Name, s.Updated, s.ExpiresAfter)
+//	}
+//
+// List is only available to privileged workloads; that is, clerk workloads
+// and VSecM Sentinel, unless the Grants of the Config tell otherwise.
+func (c *Client) List(ctx context.Context) ([]SecretInfo, error) {
+	id, err := c.identify(OpList)
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Log("Sentry:List svid:id: ", id)
+
+	code, body, err := c.send(
+		ctx, "list", http.MethodGet, "/sentinel/v1/secrets", nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var slr api.SecretListResponse
+	err = receive("list", code, body, &slr)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]SecretInfo, 0, len(slr.Secrets))
+	for _, s := range slr.Secrets {
+		secrets = append(secrets, secretInfo(s))
+	}
+
+	return secrets, nil
+}
+
+// ListEncrypted returns every secret in VSecM Safe along with its encrypted
+// value, and the algorithm that the values are encrypted with.
+//
+// ListEncrypted is only available to privileged workloads; that is, clerk
+// workloads and VSecM Sentinel, unless the OpList Grants of the Config tell
+// otherwise.
+func (c *Client) ListEncrypted(
+	ctx context.Context,
+) (EncryptedSecretList, error) {
+	id, err := c.identify(OpList)
+	if err != nil {
+		return EncryptedSecretList{}, err
+	}
+
+	debug.Log("Sentry:ListEncrypted svid:id: ", id)
+
+	code, body, err := c.send(
+		ctx, "list", http.MethodGet, "/sentinel/v1/secrets?reveal=true", nil,
+	)
+	if err != nil {
+		return EncryptedSecretList{}, err
+	}
+
+	var slr api.SecretEncryptedListResponse
+	err = receive("list", code, body, &slr)
+	if err != nil {
+		return EncryptedSecretList{}, err
+	}
+
+	list := EncryptedSecretList{
+		Algorithm: string(slr.Algorithm),
+		Secrets:   make([]EncryptedSecret, 0, len(slr.Secrets)),
+	}
+	for _, s := range slr.Secrets {
+		list.Secrets = append(list.Secrets, encryptedSecret(s))
+	}
+
+	return list, nil
+}
@@ -11,62 +11,151 @@
 package sentry
 
 import (
-	"bufio"
+	"context"
 	"errors"
+	"log"
+
 	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
-	"os"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
 )
 
-func saveData(data string) error {
-	path := env.SecretsPathForSidecar()
+// logWarning logs v with the standard logger, unless VSECM_LOG_LEVEL is
+// below the warning level.
+func logWarning(v ...any) {
+	if env.LogLevel() >= int(env.Warn) {
+		log.Println(append([]any{"VSecM sidecar:"}, v...)...)
+	}
+}
+
+// clientFunc returns the Client to fetch the secret through; it is either
+// a given Client, or the package-level Client.
+type clientFunc func(ctx context.Context) (*Client, error)
+
+// sidecar is the state of a Watch loop.
+type sidecar struct {
+	client clientFunc
+	state  fetchState
+	sinks  *sinkWriter
+	hooks  []Hook
+	status *watchStatus
 
-	f, err := os.Create(path)
+	deletion deletion
+}
+
+func newSidecar(
+	client clientFunc, cfg Config, status *watchStatus,
+) (*sidecar, error) {
+	// The Client is created lazily, on the first poll; make sure that a
+	// misconfiguration stops the loop right away, rather than being
+	// retried forever.
+	err := cfg.check("watch")
 	if err != nil {
-		return errors.Join(
-			err,
-			errors.New("error saving data"),
-		)
+		return nil, err
 	}
 
-	w := bufio.NewWriter(f)
-	_, err = w.WriteString(data)
+	_, _, err = cfg.authorization("watch")
 	if err != nil {
-		return errors.Join(
-			err,
-			errors.New("error saving data"),
-		)
+		return nil, err
 	}
 
-	err = w.Flush()
+	w, err := newSinkWriter(cfg)
 	if err != nil {
-		return errors.Join(
-			err,
-			errors.New("error flushing writer"),
-		)
+		return nil, err
+	}
+
+	hooks, err := cfg.hooks()
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := cfg.deletePolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sidecar{
+		client: client,
+		sinks:  w,
+		hooks:  hooks,
+		status: status,
+		deletion: deletion{
+			policy:    policy,
+			grace:     cfg.DeleteGracePeriod,
+			tombstone: cfg.TombstonePath,
+		},
+	}, nil
+}
+
+// save writes value to the sinks, and runs the hooks if that has changed
+// the content of the sinks.
+func (s *sidecar) save(ctx context.Context, value string) error {
+	// Nothing is written again; e.g. a wiped secret on every poll.
+	if s.sinks.holds(value) {
+		return nil
+	}
+
+	changed, err := s.sinks.save(value)
+	if err != nil {
+		return err
+	}
+	s.status.wrote()
+
+	// A restarted sidecar finds the files as the previous run, or the init
+	// container, has left them; the application has nothing to reload.
+	if changed {
+		runHooks(ctx, s.hooks)
 	}
 
 	return nil
 }
 
-func fetchSecrets() error {
-	r, eFetch := Fetch()
+func (s *sidecar) fetchSecrets(ctx context.Context) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, changed, err := c.fetchIfChanged(ctx, &s.state)
+	if err == nil || errors.Is(err, ErrSecretNotFound) {
+		s.status.fetched(r.Updated)
+	}
 
+	switch {
 	// VSecM Safe was successfully queried, but no secrets found.
-	// This means someone has deleted the secret. We cannot let
-	// the workload linger with the existing secret, so we remove
-	// it from the workload too.
-	//
-	// If the user wants a more fine-tuned control for this case,
-	// that is: if the user wants to keep the existing secret even
-	// if it has been deleted from VSecM Safe, then the user should
-	// use VSecM SDK directly, instead of using VSecM Sidecar.
-	if errors.Is(eFetch, ErrSecretNotFound) {
-		return saveData("")
-	}
+	// This means someone has deleted the secret. By default, we do not
+	// let the workload linger with the existing secret, so we remove
+	// it from the workload too; the DeletePolicy of the Config tells
+	// otherwise.
+	case errors.Is(err, ErrSecretNotFound):
+		return s.deleted(ctx)
+
+	// VSecM Safe could not be reached, could not be trusted, or has
+	// refused us. The outputs are left as they are, and the caller is
+	// told, so that it tries again.
+	case err != nil:
+		return err
 
-	v := r.Data
-	if v == "" {
+	// Nothing has changed since the last time; there is no need to
+	// touch the file.
+	case !changed:
 		return nil
+
+	// The secret exists, but it has no value (yet). This is not a
+	// deletion; so the outputs are left as they are.
+	case r.Data == "":
+		debug.Log("Sentry: secret is empty; keeping the outputs as they are")
+		return nil
+	}
+
+	err = s.save(ctx, r.Data)
+	if err == nil {
+		s.restored()
+	} else {
+		// Make sure that the next poll writes the file again, even if
+		// the secret has not changed in the meantime.
+		s.state.lock.Lock()
+		s.state.reset()
+		s.state.lock.Unlock()
 	}
-	return saveData(v)
+	return err
 }
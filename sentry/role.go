@@ -0,0 +1,108 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/core/validation"
+)
+
+// Role is a kind of workload that VSecM tells apart by its SPIFFE ID. Besides
+// the built-in roles below, user-defined roles can be registered with
+// WithRole.
+type Role = validation.Role
+
+const (
+	// RoleWorkload is any workload that VSecM knows about.
+	RoleWorkload = validation.RoleWorkload
+	// RoleSafe is VSecM Safe.
+	RoleSafe = validation.RoleSafe
+	// RoleClerk is a workload that can store secrets.
+	RoleClerk = validation.RoleClerk
+	// RoleSentinel is VSecM Sentinel.
+	RoleSentinel = validation.RoleSentinel
+	// RoleScout is VSecM Scout.
+	RoleScout = validation.RoleScout
+)
+
+// Operation is a call that the Client makes to VSecM Safe on behalf of the
+// workload.
+type Operation string
+
+const (
+	OpFetch  Operation = "fetch"
+	OpStore  Operation = "store"
+	OpUpsert Operation = "upsert"
+	OpDelete Operation = "delete"
+	OpList   Operation = "list"
+)
+
+// defaultGrants are the roles that may perform each Operation, unless the
+// Grants of the Config tell otherwise.
+var defaultGrants = map[Operation][]Role{
+	OpFetch:  {RoleWorkload},
+	OpStore:  {RoleClerk},
+	OpUpsert: {RoleClerk, RoleSentinel},
+	OpDelete: {RoleClerk, RoleSentinel},
+	OpList:   {RoleClerk, RoleSentinel},
+}
+
+// grants returns the roles that may perform each Operation: the Grants of
+// the Config on top of the defaults. It returns an error if a grant names a
+// role that m does not know, or an unknown Operation.
+func (c Config) grants(m *validation.Matchers) (map[Operation][]Role, error) {
+	g := make(map[Operation][]Role, len(defaultGrants))
+	for op, roles := range defaultGrants {
+		g[op] = roles
+	}
+
+	for op, roles := range c.Grants {
+		if _, ok := defaultGrants[op]; !ok {
+			return nil, fmt.Errorf("unknown operation: %s", op)
+		}
+		for _, r := range roles {
+			if !m.Has(r) {
+				return nil, fmt.Errorf("%s: unknown role: %s", op, r)
+			}
+		}
+		g[op] = roles
+	}
+
+	return g, nil
+}
+
+// authorization compiles the SPIFFE ID patterns and the Grants of the
+// Config. It returns an error that wraps ErrInvalidConfig if any of them is
+// invalid.
+func (c Config) authorization(
+	scope string,
+) (*validation.Matchers, map[Operation][]Role, error) {
+	matchers, err := c.rules().Compile()
+	if err != nil {
+		return nil, nil, errors.Join(
+			err, fmt.Errorf("%s: %w", scope, ErrInvalidConfig),
+		)
+	}
+
+	grants, err := c.grants(matchers)
+	if err != nil {
+		return nil, nil, errors.Join(
+			err, fmt.Errorf("%s: %w", scope, ErrInvalidConfig),
+		)
+	}
+
+	return matchers, grants, nil
+}
+
+// can returns the function that tells whether a SPIFFE ID may perform op.
+func (c *Client) can(op Operation) func(string) bool {
+	roles := c.grants[op]
+	return func(spiffeid string) bool {
+		return c.matchers.IsAny(roles, spiffeid)
+	}
+}
@@ -0,0 +1,406 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+	"github.com/spiffe/vsecm-sdk-go/internal/lib/file"
+)
+
+// SinkFormat is the format that a Sink renders the secret in.
+type SinkFormat string
+
+const (
+	// SinkRaw writes the secret as is; or, if the Sink has a Template, the
+	// output of the template.
+	SinkRaw SinkFormat = "raw"
+	// SinkJSON writes the secret as JSON.
+	SinkJSON SinkFormat = "json"
+	// SinkYAML writes the secret as YAML.
+	SinkYAML SinkFormat = "yaml"
+	// SinkDotenv writes one KEY='value' line for each top-level key of the
+	// secret. Keys that are not valid shell variable names are rejected.
+	SinkDotenv SinkFormat = "dotenv"
+	// SinkFiles treats the Path of the Sink as a directory, and writes one
+	// file for each top-level key of the secret; e.g. the "password" key of
+	// a Sink at /run/secrets/db goes to /run/secrets/db/password.
+	SinkFiles SinkFormat = "files"
+)
+
+// Sink is an output of the Watch loop: a file, or a directory of files, that
+// the secret is rendered into whenever it changes.
+//
+// The secret is rendered with the same rules that VSecM Safe uses: the
+// Template, if any, is applied first; then the result is converted to the
+// Format. For SinkDotenv and SinkFiles, a secret that is not a JSON object
+// is stored under the "VALUE" key.
+type Sink struct {
+	Path     string     `json:"path" yaml:"path"`
+	Format   SinkFormat `json:"format" yaml:"format"`
+	Template string     `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// ParseSinks parses a list of Sinks from YAML or JSON, such as:
+//
+//   - path: /opt/vsecm/secrets.json
+//     format: json
+//   - path: /opt/vsecm/.env
+//     format: dotenv
+//   - path: /run/secrets/db
+//     format: files
+//     template: '{"username":"{{.user}}","password":"{{.pass}}"}'
+func ParseSinks(data []byte) ([]Sink, error) {
+	var sinks []Sink
+	err := yaml.Unmarshal(data, &sinks)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("unable to parse sinks"))
+	}
+
+	return validateSinks(sinks)
+}
+
+// validateSinks checks sinks, and fills in their defaults.
+func validateSinks(sinks []Sink) ([]Sink, error) {
+	for i, s := range sinks {
+		if s.Path == "" {
+			return nil, fmt.Errorf("sink %d: path is required", i)
+		}
+		switch s.Format {
+		case "":
+			sinks[i].Format = SinkRaw
+		case SinkRaw, SinkJSON, SinkYAML, SinkDotenv, SinkFiles:
+		default:
+			return nil, fmt.Errorf("sink %d: unknown format: %s", i, s.Format)
+		}
+	}
+
+	return sinks, nil
+}
+
+// sinksFromEnv reads the Sinks from the VSECM_SIDECAR_OUTPUTS environment
+// variable, or from the file that VSECM_SIDECAR_OUTPUTS_FILE points to. It
+// returns no Sinks if neither is set.
+func sinksFromEnv() ([]Sink, error) {
+	if o := env.OutputsForSidecar(); o != "" {
+		return ParseSinks([]byte(o))
+	}
+
+	if p := env.OutputsFileForSidecar(); p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("unable to read sinks"))
+		}
+		return ParseSinks(b)
+	}
+
+	return nil, nil
+}
+
+// sinks returns the Sinks of the Config. If the Config has none, the only
+// Sink is the SecretsPath, which receives the secret as is.
+func (c Config) sinks() ([]Sink, error) {
+	if len(c.Sinks) > 0 {
+		return validateSinks(append([]Sink(nil), c.Sinks...))
+	}
+	if c.sinksErr != nil {
+		return nil, c.sinksErr
+	}
+
+	return []Sink{{Path: c.SecretsPath, Format: SinkRaw}}, nil
+}
+
+// WriteOutputs writes value to the Sinks of the Client's Config, or to its
+// SecretsPath, the same way that the Watch loop does: atomically, and with
+// the same file mode and owner. Hooks are not run.
+//
+// It lets an init container leave the secret on a volume that it shares with
+// the application, before the sidecar takes over.
+func (c *Client) WriteOutputs(value string) error {
+	w, err := newSinkWriter(c.cfg)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.save(value)
+	return err
+}
+
+// render returns the files to write for value, keyed by their paths.
+func (s Sink) render(value string) (map[string][]byte, error) {
+	if value == "" {
+		if s.Format == SinkFiles {
+			return map[string][]byte{}, nil
+		}
+		return map[string][]byte{s.Path: nil}, nil
+	}
+
+	secret := api.SecretStored{
+		Name:  s.Path,
+		Value: value,
+		Meta:  api.SecretMeta{Template: s.Template},
+	}
+
+	switch s.Format {
+	case SinkRaw:
+		if s.Template == "" {
+			return map[string][]byte{s.Path: []byte(value)}, nil
+		}
+		secret.Meta.Format = api.Raw
+	case SinkJSON:
+		secret.Meta.Format = api.Json
+	case SinkYAML:
+		secret.Meta.Format = api.Yaml
+	case SinkDotenv:
+		out, err := dotenv(secret.ToMapForK8s())
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: %w", s.Path, err)
+		}
+		return map[string][]byte{s.Path: out}, nil
+	case SinkFiles:
+		files := map[string][]byte{}
+		for k, v := range secret.ToMapForK8s() {
+			if !filepath.IsLocal(k) {
+				return nil, fmt.Errorf("sink %s: invalid key: %s", s.Path, k)
+			}
+			files[filepath.Join(s.Path, k)] = v
+		}
+		return files, nil
+	default:
+		return nil, fmt.Errorf("sink %s: unknown format: %s", s.Path, s.Format)
+	}
+
+	out, err := secret.Parse()
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("sink %s: unable to render", s.Path))
+	}
+	return map[string][]byte{s.Path: []byte(out)}, nil
+}
+
+// dotenvKey is what a key has to look like to be a shell variable name.
+var dotenvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dotenv renders values as KEY='value' lines, sorted by key. The values are
+// single-quoted, so that neither `$`, nor backticks, nor backslashes are
+// expanded when the file is sourced.
+func dotenv(values map[string][]byte) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if !dotenvKey.MatchString(k) {
+			return nil, fmt.Errorf("invalid key: %s", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteString("='")
+		b.WriteString(strings.ReplaceAll(string(values[k]), "'", `'\''`))
+		b.WriteString("'\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// sinkWriter writes the secret to every Sink of a Config.
+type sinkWriter struct {
+	lock sync.Mutex
+
+	sinks []Sink
+	mode  os.FileMode
+	owner file.Owner
+
+	// written are the files that SinkFiles sinks have written, so that
+	// the ones that belong to keys that are gone can be removed.
+	written map[string]bool
+
+	// last is the value that has last been saved successfully.
+	saved bool
+	last  string
+}
+
+func newSinkWriter(cfg Config) (*sinkWriter, error) {
+	sinks, err := cfg.sinks()
+	if err != nil {
+		return nil, err
+	}
+
+	mode := cfg.SecretsFileMode
+	if mode == 0 {
+		// A file that nobody can read is never what the user wants.
+		mode = 0600
+	}
+
+	return &sinkWriter{
+		sinks:   sinks,
+		mode:    mode,
+		owner:   file.Owner{UID: cfg.SecretsFileUID, GID: cfg.SecretsFileGID},
+		written: map[string]bool{},
+	}, nil
+}
+
+// save renders value into every Sink, and atomically replaces their files.
+// It goes through all the Sinks even if some of them fail.
+//
+// save does nothing if value is what it has last saved. Otherwise, it
+// writes every file, so that their mode and owner follow the Config; but it
+// reports whether the content of any file has changed, so that hooks do not
+// run for the files that a previous run has already written.
+func (w *sinkWriter) save(value string) (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.saved && w.last == value {
+		return false, nil
+	}
+
+	var errs []error
+	current := map[string]bool{}
+	changed := false
+
+	for _, s := range w.sinks {
+		files, err := s.render(value)
+		if err != nil {
+			errs = append(errs, err)
+
+			// Keep the files of the sink as they are until it renders again.
+			for p := range w.written {
+				if strings.HasPrefix(p, s.Path+string(filepath.Separator)) {
+					current[p] = true
+				}
+			}
+			continue
+		}
+
+		for p, data := range files {
+			if s.Format == SinkFiles {
+				current[p] = true
+				err := os.MkdirAll(filepath.Dir(p), 0750)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+
+			old, err := os.ReadFile(p)
+			if err != nil || !bytes.Equal(old, data) {
+				changed = true
+			}
+
+			err = file.WriteAtomic(p, data, w.mode, w.owner)
+			if err != nil {
+				errs = append(errs, errors.Join(
+					err, fmt.Errorf("error saving data to %s", p),
+				))
+			}
+		}
+	}
+
+	for p := range w.written {
+		if current[p] {
+			continue
+		}
+		err := os.Remove(p)
+		switch {
+		case err == nil:
+			changed = true
+		case !errors.Is(err, os.ErrNotExist):
+			debug.Log("Sentry: problem removing stale file: ", err.Error())
+		}
+	}
+	w.written = current
+
+	err := errors.Join(errs...)
+
+	// If anything has failed, the next save shall write everything again.
+	w.saved = err == nil
+	w.last = value
+
+	return changed, err
+}
+
+// holds tells whether value is what save has last saved successfully.
+func (w *sinkWriter) holds(value string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.saved && w.last == value
+}
+
+// populated tells whether any Sink has a non-empty file on disk; e.g. one
+// that a previous run has written.
+func (w *sinkWriter) populated() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, s := range w.sinks {
+		if s.Format == SinkFiles {
+			entries, err := os.ReadDir(s.Path)
+			if err == nil && len(entries) > 0 {
+				return true
+			}
+			continue
+		}
+
+		fi, err := os.Stat(s.Path)
+		if err == nil && fi.Size() > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remove deletes the files of every Sink. It reports whether it has removed
+// anything.
+func (w *sinkWriter) remove() (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	paths := make([]string, 0, len(w.sinks)+len(w.written))
+	for _, s := range w.sinks {
+		if s.Format != SinkFiles {
+			paths = append(paths, s.Path)
+		}
+	}
+	for p := range w.written {
+		paths = append(paths, p)
+	}
+
+	removed := false
+	var errs []error
+	for _, p := range paths {
+		err := os.Remove(p)
+		switch {
+		case err == nil:
+			removed = true
+		case !errors.Is(err, os.ErrNotExist):
+			errs = append(errs, errors.Join(
+				err, fmt.Errorf("error removing %s", p),
+			))
+		}
+	}
+
+	w.written = map[string]bool{}
+	w.saved = false
+	w.last = ""
+
+	return removed, errors.Join(errs...)
+}
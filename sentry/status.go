@@ -0,0 +1,105 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"sync"
+	"time"
+)
+
+// WatchStatus is the health of a Watch loop, as returned by Status and
+// Client.Status.
+type WatchStatus struct {
+	// Running tells whether the Watch loop is running.
+	Running bool
+
+	// LastAttempt is when the Watch loop has last polled VSecM Safe.
+	LastAttempt time.Time
+	// LastSuccess is when the Watch loop has last synchronized its outputs
+	// with VSecM Safe. It is zero until the first success.
+	LastSuccess time.Time
+	// LastWrite is when the Watch loop has last written its outputs. It is
+	// zero until the outputs are first written.
+	LastWrite time.Time
+	// LastUpdated is the Updated value of the secret that VSecM Safe has
+	// last sent; empty if the secret does not exist.
+	LastUpdated string
+
+	// ConsecutiveFailures is the number of polls that have failed since
+	// the last success.
+	ConsecutiveFailures int
+	// LastError is the reason of the last failure; nil after a success.
+	LastError error
+}
+
+// watchStatus keeps the WatchStatus of a Watch loop up-to-date.
+type watchStatus struct {
+	lock   sync.RWMutex
+	status WatchStatus
+}
+
+func (s *watchStatus) get() WatchStatus {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.status
+}
+
+func (s *watchStatus) setRunning(running bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.status.Running = running
+}
+
+// record updates the status with the outcome of a poll.
+func (s *watchStatus) record(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	s.status.LastAttempt = now
+
+	if err != nil {
+		s.status.ConsecutiveFailures++
+		s.status.LastError = err
+		return
+	}
+
+	s.status.LastSuccess = now
+	s.status.ConsecutiveFailures = 0
+	s.status.LastError = nil
+}
+
+// fetched records the Updated value of the secret that has been fetched.
+func (s *watchStatus) fetched(updated string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.status.LastUpdated = updated
+}
+
+// wrote records that the outputs have been written.
+func (s *watchStatus) wrote() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.status.LastWrite = time.Now()
+}
+
+// defaultStatus is the status of the package-level Watch loop.
+var defaultStatus watchStatus
+
+// Status returns the health of the package-level Watch loop; that is, the
+// one that Watch, RunSidecar, and WatchContext run.
+func Status() WatchStatus {
+	return defaultStatus.get()
+}
+
+// Status returns the health of the Watch loop that runs on the Client.
+func (c *Client) Status() WatchStatus {
+	return c.status.get()
+}
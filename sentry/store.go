@@ -6,22 +6,10 @@
 package sentry
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"io"
+	"context"
 	"net/http"
-	"net/url"
 
-	"github.com/spiffe/go-spiffe/v2/spiffeid"
-	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
-	"github.com/spiffe/go-spiffe/v2/workloadapi"
-	"golang.org/x/net/context"
-
-	"github.com/spiffe/vsecm-sdk-go/internal/config"
-	reqres "github.com/spiffe/vsecm-sdk-go/internal/core/entity/v1/reqres/safe"
-	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
-	"github.com/spiffe/vsecm-sdk-go/internal/core/validation"
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
 	"github.com/spiffe/vsecm-sdk-go/internal/debug"
 )
 
@@ -44,171 +32,91 @@ import (
 //   - value: The secret value to store.
 //
 // Returns:
-//   - reqres.SecretStoreResponse: Contains the server's response after
+//   - api.SecretStoreResponse: Contains the server's response after
 //     storing the secret.
 //   - error: Returns nil on success. Possible errors include:
-//   - SPIFFE Workload API connection failures
-//   - Authentication/authorization failures
-//   - Network connectivity issues
-//   - Invalid workload identity
-//   - API endpoint communication errors
+//   - SPIFFE Workload API connection failures (ErrSVIDUnavailable)
+//   - Authentication/authorization failures (ErrUntrustedSafe,
+//     ErrSafeUnauthorized)
+//   - Network connectivity issues (ErrSafeUnreachable)
+//   - Invalid workload identity (ErrUntrustedWorkload)
+//   - API endpoint communication errors (*HTTPStatusError)
+//   - Rejections reported by VSecM Safe itself (*ServerError)
 //
 // The method implements several security best practices:
-//   - Uses short-lived mTLS connections with SPIFFE-based authentication
+//   - Uses mTLS connections with SPIFFE-based authentication
 //   - Validates workload identity before allowing secret storage
-//   - Implements connection pooling optimizations
+//   - Reuses the Client's connections instead of a handshake per call
 //   - Properly closes all resources to prevent leaks
 //
 // Example:
 //
-//	resp, err := Store("database-password", "secret123")
+//	resp, err := client.Store(ctx, "database-password", "secret123")
 //	if err != nil {
 //	    log.Fatalf("Failed to store secret: %v", err)
 //	}
 //
 // Note: This method is only available to workloads with clerk privileges in the
-// VSecM security model. Attempting to store secrets from unauthorized workloads
-// will result in an error.
-func Store(key, value string) (reqres.SecretStoreResponse, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var source *workloadapi.X509Source
-	source, err := workloadapi.NewX509Source(
-		ctx, workloadapi.WithClientOptions(
-			workloadapi.WithAddr(env.SpiffeSocketUrl()),
-		),
-	)
-	if err != nil {
-		return reqres.SecretStoreResponse{},
-			errors.Join(
-				err,
-				errors.New(
-					"store: failed getting SVID Bundle from the SPIFFE Workload API",
-				),
-			)
-	}
-
-	defer func(s *workloadapi.X509Source) {
-		if s == nil {
-			return
-		}
-		err := s.Close()
-		if err != nil {
-			if config.SdkConfig.Debug {
-				debug.Log("store: problem closing source: ", err.Error())
-			}
-		}
-	}(source)
-
-	svid, err := source.GetX509SVID()
-	if err != nil {
-		return reqres.SecretStoreResponse{},
-			errors.Join(
-				err,
-				errors.New("store: error getting SVID from source"),
-			)
-	}
-
+// VSecM security model, unless the Grants of the Config tell otherwise.
+// Attempting to store secrets from unauthorized workloads will result in an
+// error.
+func (c *Client) Store(
+	ctx context.Context, key, value string,
+) (api.SecretStoreResponse, error) {
 	// Make sure that we are calling Safe from a workload that can write
 	// raw secrets.
-	if !validation.IsClerk(svid.ID.String()) {
-		return reqres.SecretStoreResponse{},
-			errors.New("store: untrusted workload: '" + svid.ID.String() + "'")
-	}
-
-	authorizer := tlsconfig.AdaptMatcher(func(id spiffeid.ID) error {
-		if validation.IsSafe(id.String()) {
-			return nil
-		}
-
-		return errors.New("store: I don't know you, and it's crazy: '" +
-			id.String() + "'")
-	})
-
-	p, err := url.JoinPath(env.EndpointUrlForSafe(), "/workload/v1/secrets")
+	id, err := c.identify(OpStore)
 	if err != nil {
-		return reqres.SecretStoreResponse{},
-			errors.New("fetch: problem generating server url")
-	}
-
-	client := &http.Client{
-		Transport: &http.Transport{
-			// Use the connection to serve a single http request only.
-			// This is not a web server; there is no need to keep the
-			// connection open for multiple requests. This will also
-			// save a good chunk of memory, especially when polling
-			// interval is shorter. [1]
-			DisableKeepAlives: true,
-			TLSClientConfig:   tlsconfig.MTLSClientConfig(source, source, authorizer),
-		},
+		return api.SecretStoreResponse{}, err
 	}
 
-	debug.Log("Sentry:Store", p)
-	debug.Log("Sentry:Store svid:id: ", svid.ID.String())
+	debug.Log("Sentry:Store svid:id: ", id)
 
-	sr := &reqres.SecretStoreRequest{
+	sr := &api.SecretStoreRequest{
 		Key:   "raw:" + key,
 		Value: value,
 	}
 
-	md, err := json.Marshal(sr)
+	code, body, err := c.send(
+		ctx, "store", http.MethodPost, "/workload/v1/secrets", sr,
+	)
 	if err != nil {
-		return reqres.SecretStoreResponse{}, errors.Join(
-			err,
-			errors.New("store: I am having problem generating the payload"),
-		)
+		return api.SecretStoreResponse{}, err
 	}
 
-	r, err := client.Post(p, "application/json", bytes.NewBuffer(md))
-
+	var ssr api.SecretStoreResponse
+	err = receive("store", code, body, &ssr)
 	if err != nil {
-		return reqres.SecretStoreResponse{}, errors.Join(
-			err,
-			errors.New("store: Problem connecting to VSecM Safe API endpoint URL"),
-		)
-	}
-
-	if r.StatusCode != http.StatusOK {
-		return reqres.SecretStoreResponse{},
-			errors.New("store: Problem connecting to VSecM Safe API endpoint URL")
+		return api.SecretStoreResponse{}, err
 	}
 
-	defer func(b io.ReadCloser) {
-		err := b.Close()
-		if err != nil {
-			if err != nil {
-				debug.Log("Fetch: problem closing response body: ", err.Error())
-			}
-		}
-	}(r.Body)
-
-	// Hint the server that we wish to close the connection
-	// as soon as we are done with it.
-	r.Close = true
-
-	if r.StatusCode == http.StatusNotFound {
-		return reqres.SecretStoreResponse{}, ErrSecretNotFound
-	}
+	return ssr, nil
+}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		return reqres.SecretStoreResponse{}, errors.Join(
-			err,
-			errors.New(
-				"unable to read the response body from VSecM Safe API endpoint",
-			),
-		)
-	}
+// Store securely saves a secret value associated with a key in the VSecM Safe
+// storage. It prepends "raw:" to the provided key before storage.
+//
+//	resp, err := sentry.Store("database-password", "secret123")
+//
+// Store is equivalent to StoreContext with context.Background().
+// See Client.Store for details.
+func Store(key, value string) (api.SecretStoreResponse, error) {
+	return StoreContext(context.Background(), key, value)
+}
 
-	var ssr reqres.SecretStoreResponse
-	err = json.Unmarshal(body, &ssr)
+// StoreContext is like Store, but it honors the cancellation and the deadline
+// of ctx while connecting to the SPIFFE Workload API and while talking to
+// VSecM Safe. If ctx is done, StoreContext returns ctx.Err().
+//
+// StoreContext uses a package-level Client that is created on first use and
+// reused afterward. Use New to create a dedicated Client instead.
+func StoreContext(
+	ctx context.Context, key, value string,
+) (api.SecretStoreResponse, error) {
+	c, err := getDefaultClient(ctx)
 	if err != nil {
-		return reqres.SecretStoreResponse{}, errors.Join(
-			err,
-			errors.New("unable to deserialize response"),
-		)
+		return api.SecretStoreResponse{}, err
 	}
 
-	return ssr, nil
+	return c.Store(ctx, key, value)
 }
@@ -0,0 +1,164 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// EventType tells what has happened to the secret of the workload.
+type EventType string
+
+const (
+	// EventUpdated means the secret has been created, or its value has
+	// changed.
+	EventUpdated EventType = "updated"
+	// EventDeleted means a secret that has been delivered before does not
+	// exist anymore.
+	EventDeleted EventType = "deleted"
+	// EventError means the secret could not be fetched. The subscription
+	// keeps polling.
+	EventError EventType = "error"
+)
+
+// Event is a change in the secret of the workload, as delivered by
+// Subscribe and SubscribeFunc.
+type Event struct {
+	Type EventType
+
+	// Old is the secret that has been delivered before, if any.
+	// It is set for EventUpdated and EventDeleted.
+	Old api.SecretFetchResponse
+	// New is the up-to-date secret. It is set for EventUpdated.
+	New api.SecretFetchResponse
+
+	// Err is the reason of the failure. It is set for EventError.
+	Err error
+}
+
+// SubscribeOptions configures a subscription.
+type SubscribeOptions struct {
+	// Interval is the time between two polls.
+	// Defaults to the PollInterval of the Client's Config.
+	Interval time.Duration
+
+	// Buffer is the capacity of the channel that Subscribe returns.
+	// Defaults to 1.
+	Buffer int
+}
+
+// SubscribeFunc polls VSecM Safe for the secret of the workload, and calls fn
+// every time the secret changes, until ctx is done. It then returns ctx.Err().
+//
+//	err := client.SubscribeFunc(ctx, sentry.SubscribeOptions{},
+//	    func(e sentry.Event) {
+//	        if e.Type == sentry.EventUpdated {
+//	            reload(e.New.Data)
+//	        }
+//	    },
+//	)
+//
+// Events are de-duplicated by the content of the secret: fn is not called
+// again until the value of the secret actually changes, even if VSecM Safe
+// bumps its Updated time. The first successful poll always delivers an
+// EventUpdated, so fn learns about the initial value.
+//
+// fn is called from the polling goroutine; polling waits for fn to return.
+func (c *Client) SubscribeFunc(
+	ctx context.Context, opts SubscribeOptions, fn func(Event),
+) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = c.cfg.PollInterval
+	}
+
+	var (
+		st       fetchState
+		seen     bool
+		lastHash [sha256.Size]byte
+		last     api.SecretFetchResponse
+	)
+
+	for {
+		r, changed, err := c.fetchIfChanged(ctx, &st)
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, ErrSecretNotFound):
+			if seen {
+				fn(Event{Type: EventDeleted, Old: last})
+				seen = false
+				last = api.SecretFetchResponse{}
+			}
+		case err != nil:
+			debug.Log("Sentry:Subscribe", "could not fetch secret:", err.Error())
+			fn(Event{Type: EventError, Err: err})
+		case changed:
+			h := sha256.Sum256([]byte(r.Data))
+			if !seen || h != lastHash {
+				fn(Event{Type: EventUpdated, Old: last, New: r})
+			}
+			seen = true
+			lastHash = h
+			last = r
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Subscribe is like SubscribeFunc, but it delivers the events over the
+// returned channel instead. The channel is closed once ctx is done.
+//
+//	for e := range client.Subscribe(ctx, sentry.SubscribeOptions{}) {
+//	    switch e.Type {
+//	    case sentry.EventUpdated:
+//	        reload(e.New.Data)
+//	    case sentry.EventDeleted:
+//	        forget()
+//	    case sentry.EventError:
+//	        log.Println("secret sync failed:", e.Err)
+//	    }
+//	}
+//
+// Polling waits while the channel is full; a slow reader delays the next
+// poll, but never misses an event.
+func (c *Client) Subscribe(
+	ctx context.Context, opts SubscribeOptions,
+) <-chan Event {
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = 1
+	}
+
+	events := make(chan Event, buffer)
+
+	go func() {
+		defer close(events)
+
+		_ = c.SubscribeFunc(ctx, opts, func(e Event) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return events
+}
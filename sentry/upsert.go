@@ -0,0 +1,126 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package sentry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+)
+
+// SecretFormat is the format that VSecM Safe renders a secret in before
+// handing it to the workload.
+type SecretFormat = api.SecretFormat
+
+const (
+	// FormatAuto asks the decoding functions to detect the format.
+	FormatAuto SecretFormat = ""
+
+	FormatJSON = api.Json
+	FormatYAML = api.Yaml
+	FormatRaw  = api.Raw
+)
+
+// UpsertRequest describes a secret to create, or to update, in VSecM Safe.
+type UpsertRequest struct {
+	// WorkloadIDs are the names of the workloads that the secret is bound
+	// to. At least one is required.
+	WorkloadIDs []string
+
+	// Namespaces are the Kubernetes namespaces of the workloads.
+	// VSecM Safe defaults to "default" if none is given.
+	Namespaces []string
+
+	// Value is the value of the secret. If Encrypt is true, Value shall be
+	// encrypted with the public key of VSecM Safe.
+	Value string
+
+	// Template is an optional Go template that transforms the value before
+	// it is delivered to the workloads.
+	// Sample value:
+	// '{"username":"admin","password":"VSecMRocks"}'
+	// Sample template:
+	// '{"USER":"{{.username}}", "PASS":"{{.password}}"}"
+	Template string
+
+	// Format is the format of the transformed value.
+	Format SecretFormat
+
+	// Encrypt indicates that Value is encrypted.
+	Encrypt bool
+
+	// NotBefore is the time before which the secret is not valid.
+	// The zero value means the secret is valid right away.
+	NotBefore time.Time
+
+	// Expires is the time after which the secret is not valid.
+	// The zero value means the secret never expires.
+	Expires time.Time
+}
+
+// wire converts the UpsertRequest into what VSecM Safe expects.
+func (r UpsertRequest) wire() api.SecretUpsertRequest {
+	sr := api.SecretUpsertRequest{
+		WorkloadIds: r.WorkloadIDs,
+		Namespaces:  r.Namespaces,
+		Value:       r.Value,
+		Template:    r.Template,
+		Format:      r.Format,
+		Encrypt:     r.Encrypt,
+	}
+
+	if !r.NotBefore.IsZero() {
+		sr.NotBefore = r.NotBefore.Format(time.RFC3339)
+	}
+	if !r.Expires.IsZero() {
+		sr.Expires = r.Expires.Format(time.RFC3339)
+	}
+
+	return sr
+}
+
+// Upsert creates, or updates, a secret in VSecM Safe, and binds it to the
+// workloads of the request.
+//
+//	err := client.Upsert(ctx, sentry.UpsertRequest{
+//	    WorkloadIDs: []string{"example"},
+//	    Value:       `{"username":"admin","password":"VSecMRocks"}`,
+//	    Template:    `{"USER":"{{.username}}","PASS":"{{.password}}"}`,
+//	    Format:      sentry.FormatYAML,
+//	    Expires:     time.Now().Add(24 * time.Hour),
+//	})
+//
+// Upsert is only available to privileged workloads; that is, clerk workloads
+// and VSecM Sentinel, unless the Grants of the Config tell otherwise. Calling
+// it from any other workload returns an error that wraps ErrUntrustedWorkload,
+// without contacting VSecM Safe.
+func (c *Client) Upsert(ctx context.Context, req UpsertRequest) error {
+	id, err := c.identify(OpUpsert)
+	if err != nil {
+		return err
+	}
+
+	if len(req.WorkloadIDs) == 0 {
+		return fmt.Errorf(
+			"upsert: %w: at least one workload is required", ErrInvalidRequest,
+		)
+	}
+
+	debug.Log("Sentry:Upsert svid:id: ", id)
+
+	code, body, err := c.send(
+		ctx, "upsert", http.MethodPost, "/sentinel/v1/secrets", req.wire(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return receive("upsert", code, body, nil)
+}
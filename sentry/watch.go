@@ -6,27 +6,145 @@
 package sentry
 
 import (
-	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
 	"github.com/spiffe/vsecm-sdk-go/internal/lib/backoff"
+	"github.com/spiffe/vsecm-sdk-go/internal/lib/health"
 )
 
 // Watch synchronizes the internal state of the sidecar by talking to
 // VSecM Safe regularly. It periodically calls Fetch behind-the-scenes to
-// get its work done. Once it fetches the secrets, it saves it to
+// get its work done. Once it fetches the secrets, if they have changed since
+// the last poll, it saves them to
 // the location defined in the `VSECM_SIDECAR_SECRETS_PATH` environment
 // variable (`/opt/vsecm/secrets.json` by default).
+//
+// Watch does not handle signals; the process keeps its default behavior on
+// SIGTERM and SIGINT. It never returns; if it is misconfigured, it prints
+// the reason to stderr and exits the process with a non-zero code. Use
+// RunSidecar to stop gracefully on signals, WatchContext to be able to stop
+// it otherwise, and Status to check its health while it runs.
 func Watch() {
-	interval := env.PollIntervalForSidecar()
+	err := WatchContext(context.Background())
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "VSecM sidecar:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// RunSidecar is the entry point of a process that is a VSecM sidecar: it is
+// like Watch, but it stops gracefully once the process receives SIGTERM or
+// SIGINT; that is, after any secret that is being written is written
+// completely. It then returns an error that wraps ErrWatchStopped and a
+// SignalError:
+//
+//	func main() {
+//	    err := sentry.RunSidecar()
+//	    var sig *sentry.SignalError
+//	    if !errors.As(err, &sig) {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+// RunSidecar consumes SIGTERM and SIGINT while it runs, so do not use it
+// inside an application that relies on their default behavior.
+func RunSidecar() error {
+	ctx, stop := notifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	return WatchContext(ctx)
+}
+
+// WatchContext is like Watch, but it stops as soon as ctx is done, including
+// while it is waiting between polls or between retries. It does not handle
+// signals itself.
+//
+// Once it stops, WatchContext returns an error that wraps ErrWatchStopped
+// along with ctx.Err() and context.Cause(ctx). It returns an error right
+// away if its outputs or hooks are misconfigured; or one that wraps
+// ErrInvalidConfig if its SPIFFE ID patterns, Grants, or PollInterval are.
+// A secret that is being written when ctx is done is still written
+// completely, so the files are never left half-written.
+//
+// Failed polls, including the ones where VSecM Safe cannot be reached or the
+// SVID of the workload is not available, are retried right away with a
+// backoff, and are counted by Status; the outputs are left as they are
+// until a poll succeeds.
+//
+// The secret can be written to several files, in several formats, by
+// listing them in the VSECM_SIDECAR_OUTPUTS environment variable, or in the
+// file that VSECM_SIDECAR_OUTPUTS_FILE points to. See ParseSinks.
+//
+// The application can be told to reload the secret whenever it changes by
+// listing hooks in the VSECM_SIDECAR_HOOKS environment variable, or in the
+// file that VSECM_SIDECAR_HOOKS_FILE points to. See ParseHooks.
+//
+// When the secret is deleted from VSecM Safe, the outputs are emptied,
+// unless VSECM_SIDECAR_DELETE_POLICY tells otherwise. See DeletePolicy.
+//
+// If VSECM_SIDECAR_HEALTH_BIND_ADDR is set (e.g. ":8087"), WatchContext
+// serves /healthz, /readyz, and /status on that address for Kubernetes
+// probes. /readyz fails until the secret is first written, and whenever no
+// poll has succeeded for VSECM_SIDECAR_STALE_POLLS poll intervals (3 by
+// default).
+func WatchContext(ctx context.Context) error {
+	return watch(ctx, getDefaultClient, DefaultConfig(), &defaultStatus)
+}
+
+// Watch is like the package-level WatchContext, but it fetches the secret
+// through the Client, and it polls at the PollInterval and saves the secret
+// to the Sinks, or to the SecretsPath, of the Client's Config.
+func (c *Client) Watch(ctx context.Context) error {
+	return watch(ctx, func(context.Context) (*Client, error) {
+		return c, nil
+	}, c.cfg, &c.status)
+}
+
+func watch(
+	ctx context.Context, client clientFunc, cfg Config, status *watchStatus,
+) error {
+	interval := cfg.PollInterval
+
+	// Each watch loop keeps track of the changes on its own.
+	s, err := newSidecar(client, cfg, status)
+	if err != nil {
+		return err
+	}
+
+	status.setRunning(true)
+	defer status.setRunning(false)
+
+	if cfg.HealthBindAddr != "" {
+		done, err := health.Serve(
+			ctx, cfg.HealthBindAddr, newWatchHealth(status, cfg),
+		)
+		if err != nil {
+			return err
+		}
+		// The loop only returns once ctx is done; wait for the server to
+		// release its address too.
+		defer func() {
+			<-done
+		}()
+	}
 
 	for {
-		_ = backoff.Retry("sentry.Watch", func() error {
-			err := fetchSecrets()
+		_ = backoff.RetryContext(ctx, "sentry.Watch", func() error {
+			err := s.fetchSecrets(ctx)
+			if ctx.Err() != nil {
+				// Stopping is not a failure of VSecM Safe.
+				return ctx.Err()
+			}
+			status.record(err)
 			if err != nil {
-				debug.Log("Could not fetch secrets", err.Error(),
-					". Will retry in", interval, ".")
+				logWarning("could not fetch secrets:", err.Error(),
+					"; will retry in", interval.String())
 			}
 			return err
 		}, backoff.Strategy{
@@ -35,6 +153,47 @@ func Watch() {
 			Exponential: false,
 		})
 
-		time.Sleep(interval)
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return stopped(ctx)
+		case <-t.C:
+		}
+	}
+}
+
+// stopped returns the error that tells why the Watch loop has stopped.
+func stopped(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	debug.Log("Sentry: watch stopped:", cause.Error())
+
+	if cause == ctx.Err() {
+		return fmt.Errorf("%w: %w", ErrWatchStopped, cause)
+	}
+	return fmt.Errorf("%w: %w: %w", ErrWatchStopped, cause, ctx.Err())
+}
+
+// notifyContext is like signal.NotifyContext, but the cause of the returned
+// context is a SignalError that tells which signal has been received.
+func notifyContext(
+	parent context.Context, sig ...os.Signal,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+
+	go func() {
+		select {
+		case s := <-ch:
+			cancel(&SignalError{Signal: s})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(ch)
+		cancel(context.Canceled)
 	}
 }
@@ -0,0 +1,97 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package startup
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// initHealth tells the health server how the init container is doing.
+type initHealth struct {
+	lock sync.RWMutex
+
+	running             bool
+	lastCheck           time.Time
+	consecutiveFailures int
+	lastError           error
+	initialized         bool
+}
+
+func (h *initHealth) setRunning(running bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.running = running
+}
+
+// record updates the health with the outcome of a check.
+func (h *initHealth) record(err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.lastCheck = time.Now()
+	h.initialized = err == nil
+	h.lastError = err
+	if err == nil {
+		h.consecutiveFailures = 0
+	} else {
+		h.consecutiveFailures++
+	}
+}
+
+func (h *initHealth) Live() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if !h.running {
+		return errors.New("init container is not polling")
+	}
+	return nil
+}
+
+func (h *initHealth) Ready() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if !h.initialized {
+		if h.lastError != nil {
+			return h.lastError
+		}
+		return errors.New("secret is not available yet")
+	}
+	return nil
+}
+
+// initStatusJSON is the health of the init container as /status renders it.
+type initStatusJSON struct {
+	Running             bool       `json:"running"`
+	LastCheck           *time.Time `json:"lastCheck,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	Initialized         bool       `json:"initialized"`
+}
+
+func (h *initHealth) Status() any {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	j := initStatusJSON{
+		Running:             h.running,
+		ConsecutiveFailures: h.consecutiveFailures,
+		Initialized:         h.initialized,
+	}
+	if h.lastError != nil {
+		j.LastError = h.lastError.Error()
+	}
+	if !h.lastCheck.IsZero() {
+		t := h.lastCheck
+		j.LastCheck = &t
+	}
+
+	return j
+}
@@ -0,0 +1,210 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/spiffe/vsecm-sdk-go/api/v1"
+	"github.com/spiffe/vsecm-sdk-go/sentry"
+)
+
+// Check is what a Predicate looks at.
+type Check struct {
+	// Secret is the secret of the workload, as fetched for this check. It is
+	// the zero value if the workload has no secret.
+	Secret api.SecretFetchResponse
+
+	// Client is the Client that has fetched Secret. Predicates can use it
+	// to ask VSecM Safe for more.
+	Client *sentry.Client
+}
+
+// Predicate tells whether the workload is ready. It returns nil if it is, or
+// the reason why it is not.
+//
+// Predicates are called once per check, in order; the first one that fails
+// fails the check.
+type Predicate func(ctx context.Context, c Check) error
+
+// NotEmpty is ready once the secret of the workload exists and is not empty.
+// It is the Predicate that WaitUntilReady uses if Options has none.
+func NotEmpty() Predicate {
+	return func(_ context.Context, c Check) error {
+		if c.Secret.Data == "" {
+			return errors.New("secret does not exist, or it is empty")
+		}
+		return nil
+	}
+}
+
+// ParsesAs is ready once the secret of the workload can be parsed in format;
+// that is sentry.FormatJSON, or sentry.FormatYAML.
+func ParsesAs(format sentry.SecretFormat) Predicate {
+	return func(_ context.Context, c Check) error {
+		var v any
+		return sentry.Decode(c.Secret.Data, format, &v)
+	}
+}
+
+// HasKeys is ready once the secret of the workload is a JSON or a YAML object
+// that has every one of keys at its top level.
+func HasKeys(keys ...string) Predicate {
+	return func(_ context.Context, c Check) error {
+		var m map[string]any
+		err := sentry.Decode(c.Secret.Data, sentry.FormatAuto, &m)
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		for _, k := range keys {
+			if _, ok := m[k]; !ok {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("secret is missing keys: %s",
+				strings.Join(missing, ", "))
+		}
+
+		return nil
+	}
+}
+
+// HasPaths is ready once the secret of the workload is a JSON or a YAML
+// document that has a value at every one of paths. A path is a list of
+// object keys and array indices separated by dots, with an optional leading
+// "$."; e.g. "db.hosts.0" or "$.db.password".
+func HasPaths(paths ...string) Predicate {
+	return func(_ context.Context, c Check) error {
+		var doc any
+		err := sentry.Decode(c.Secret.Data, sentry.FormatAuto, &doc)
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		for _, p := range paths {
+			if !hasPath(doc, p) {
+				missing = append(missing, p)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("secret is missing paths: %s",
+				strings.Join(missing, ", "))
+		}
+
+		return nil
+	}
+}
+
+func hasPath(doc any, path string) bool {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc != nil
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		switch v := doc.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return false
+			}
+			doc = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return false
+			}
+			doc = v[i]
+		default:
+			return false
+		}
+	}
+
+	return doc != nil
+}
+
+// WithinValidity is ready once every one of the named secrets exists, and
+// the current time is between its NotBefore and ExpiresAfter times. A zero
+// time does not bound the window.
+//
+// VSecM Safe does not send the NotBefore and ExpiresAfter times along with
+// the secret of a workload; only sentry.Client.List has them, and only
+// privileged workloads may call it. So, WithinValidity cannot check the
+// secret of an ordinary workload from its own init container: it fails with
+// an error that wraps sentry.ErrUntrustedWorkload there. It is meant for
+// the init containers of clerk workloads, or of workloads that the Client
+// grants sentry.OpList to.
+func WithinValidity(names ...string) Predicate {
+	return func(ctx context.Context, c Check) error {
+		secrets, err := c.Client.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]sentry.SecretInfo, len(secrets))
+		for _, s := range secrets {
+			byName[s.Name] = s
+		}
+
+		now := time.Now()
+		for _, n := range names {
+			s, ok := byName[n]
+			switch {
+			case !ok:
+				return fmt.Errorf("secret %s does not exist", n)
+			case !s.NotBefore.IsZero() && now.Before(s.NotBefore):
+				return fmt.Errorf("secret %s is not valid before %s",
+					n, s.NotBefore.Format(time.RFC3339))
+			case !s.ExpiresAfter.IsZero() && now.After(s.ExpiresAfter):
+				return fmt.Errorf("secret %s has expired at %s",
+					n, s.ExpiresAfter.Format(time.RFC3339))
+			}
+		}
+
+		return nil
+	}
+}
+
+// RawSecretsExist is ready once every one of the named raw secrets exists;
+// that is, the secrets that sentry.Client.Store has stored.
+//
+// RawSecretsExist lists the secrets of VSecM Safe, so the workload shall be
+// a privileged one; see sentry.Client.List.
+func RawSecretsExist(names ...string) Predicate {
+	return func(ctx context.Context, c Check) error {
+		secrets, err := c.Client.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		exists := make(map[string]bool, len(secrets))
+		for _, s := range secrets {
+			exists[s.Name] = true
+		}
+
+		var missing []string
+		for _, n := range names {
+			if !exists["raw:"+n] {
+				missing = append(missing, n)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("raw secrets do not exist: %s",
+				strings.Join(missing, ", "))
+		}
+
+		return nil
+	}
+}
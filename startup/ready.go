@@ -0,0 +1,189 @@
+// VMware Secrets Manager (VSecM) Go SDK -- https://vsecm.com
+// Copyright 2024-present VSecM SDK contributors.
+// SPDX-License-Identifier: Apache-2.0
+// Keep your secrets... secret.
+
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spiffe/vsecm-sdk-go/internal/core/env"
+	"github.com/spiffe/vsecm-sdk-go/internal/debug"
+	"github.com/spiffe/vsecm-sdk-go/internal/lib/health"
+	"github.com/spiffe/vsecm-sdk-go/sentry"
+)
+
+// ErrNotReady is returned by WaitUntilReady when the workload has not become
+// ready in time. The returned error also wraps ctx.Err().
+var ErrNotReady = errors.New("workload is not ready")
+
+// Options configures WaitUntilReady.
+type Options struct {
+	// Interval is the time between two checks.
+	// Env: VSECM_INIT_CONTAINER_POLL_INTERVAL (in milliseconds)
+	Interval time.Duration
+
+	// Timeout bounds the whole wait. Zero waits for as long as the context
+	// allows.
+	// Env: VSECM_INIT_CONTAINER_TIMEOUT (in milliseconds)
+	Timeout time.Duration
+
+	// HealthBindAddr is the address that /healthz, /readyz, and /status
+	// are served on while waiting. No server is started if it is empty.
+	// Env: VSECM_INIT_CONTAINER_HEALTH_BIND_ADDR
+	HealthBindAddr string
+
+	// Client is the Client to fetch the secret through. If it is nil,
+	// WaitUntilReady creates one from the environment variables, and
+	// closes it when it returns.
+	Client *sentry.Client
+
+	// Predicates tell whether the workload is ready; all of them have to
+	// hold. Defaults to NotEmpty.
+	//
+	// WithinValidity and RawSecretsExist list the secrets of VSecM Safe,
+	// which only VSecM Clerk and VSecM Sentinel may do by default; a plain
+	// workload cannot use them unless the Client grants sentry.OpList to its
+	// role. Otherwise, the first check fails with an error that wraps
+	// sentry.ErrUntrustedWorkload, and WaitUntilReady returns it right away.
+	Predicates []Predicate
+
+	// Persist tells WaitUntilReady to write the secret, once it is ready,
+	// to the outputs that the sidecar writes it to; so that the application
+	// finds it there as soon as it starts. The outputs are configured with
+	// the same environment variables as the sidecar; see
+	// sentry.Client.WriteOutputs. A failed write fails the check.
+	// Env: VSECM_INIT_CONTAINER_PERSIST_SECRET ("true" to enable)
+	Persist bool
+
+	// Log receives the reason of every failed check. Defaults to logging
+	// the reason with the standard logger, unless VSECM_LOG_LEVEL is below
+	// the warning level.
+	Log func(reason error)
+}
+
+// DefaultOptions returns the Options built from the environment variables.
+func DefaultOptions() Options {
+	return Options{
+		Interval:       env.PollIntervalForInitContainer(),
+		Timeout:        env.TimeoutForInitContainer(),
+		HealthBindAddr: env.HealthBindAddrForInitContainer(),
+		Persist:        env.PersistSecretForInitContainer(),
+	}
+}
+
+// WaitUntilReady polls the associated secret of the workload until every one
+// of opts.Predicates holds; by default, until the secret exists and it is not
+// empty. It then returns nil. Unlike Watch, it never exits the
+// process, so it can be used as a library:
+//
+//	opts := startup.DefaultOptions()
+//	opts.Predicates = []startup.Predicate{
+//	    startup.ParsesAs(sentry.FormatJSON),
+//	    startup.HasKeys("username", "password"),
+//	}
+//	err := startup.WaitUntilReady(ctx, opts)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// The first check happens right away. If ctx is done, or opts.Timeout
+// elapses, first, WaitUntilReady returns an error that wraps ErrNotReady and
+// ctx.Err(), and tells the reason of the last failed check. It returns an
+// error that wraps sentry.ErrInvalidConfig right away if the SPIFFE ID
+// patterns of the environment are invalid, and one that wraps
+// sentry.ErrUntrustedWorkload if the workload may not do what the check
+// needs.
+func WaitUntilReady(ctx context.Context, opts Options) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = env.PollIntervalForInitContainer()
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	predicates := opts.Predicates
+	if len(predicates) == 0 {
+		predicates = []Predicate{NotEmpty()}
+	}
+
+	logReason := opts.Log
+	if logReason == nil {
+		logReason = logNotReady
+	}
+
+	w := &waiter{
+		client:     opts.Client,
+		connect:    interval,
+		predicates: predicates,
+		persist:    opts.Persist,
+	}
+	defer w.close()
+
+	h := &initHealth{}
+	h.setRunning(true)
+	defer h.setRunning(false)
+
+	if opts.HealthBindAddr != "" {
+		// The health server shall not outlive WaitUntilReady.
+		sCtx, cancel := context.WithCancel(ctx)
+		done, err := health.Serve(sCtx, opts.HealthBindAddr, h)
+		if err != nil {
+			cancel()
+			return err
+		}
+		defer func() {
+			cancel()
+			<-done
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		debug.Log("init:: tick")
+
+		err := w.check(ctx)
+		h.record(err)
+		if err == nil {
+			debug.Log("init:: initialized")
+			return nil
+		}
+
+		// Waiting fixes neither the configuration nor the identity of the
+		// workload.
+		if errors.Is(err, sentry.ErrInvalidConfig) ||
+			errors.Is(err, sentry.ErrUntrustedWorkload) {
+			return err
+		}
+
+		if ctx.Err() == nil {
+			logReason(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(fmt.Errorf("%w after %s: %w", ErrNotReady,
+				time.Since(start).Round(time.Millisecond), ctx.Err()), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// logNotReady logs the reason of a failed check as a warning.
+func logNotReady(reason error) {
+	if env.LogLevel() >= int(env.Warn) {
+		log.Println("VSecM init container: not ready:", reason.Error())
+	}
+}
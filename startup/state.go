@@ -10,10 +10,71 @@
 
 package startup
 
-import "github.com/spiffe/vsecm-sdk-go/sentry"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
 
-func initialized() bool {
-	r, _ := sentry.Fetch()
-	v := r.Data
-	return v != ""
+	"github.com/spiffe/vsecm-sdk-go/sentry"
+)
+
+// waiter runs the checks of WaitUntilReady.
+type waiter struct {
+	client *sentry.Client
+	// owned tells whether the waiter has created client, and shall close it.
+	owned bool
+	// connect bounds the time that creating client can take.
+	connect time.Duration
+
+	predicates []Predicate
+	// persist tells whether to write the secret to the outputs of the
+	// Client once it is ready.
+	persist bool
+}
+
+// check returns nil if the workload is ready, or the reason why it is not.
+func (w *waiter) check(ctx context.Context) error {
+	if w.client == nil {
+		cCtx, cancel := context.WithTimeout(ctx, w.connect)
+		c, err := sentry.New(cCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, sentry.ErrSVIDUnavailable) ||
+				errors.Is(err, sentry.ErrInvalidConfig) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", sentry.ErrSVIDUnavailable, err)
+		}
+		w.client = c
+		w.owned = true
+	}
+
+	r, err := w.client.Fetch(ctx)
+	if err != nil && !errors.Is(err, sentry.ErrSecretNotFound) {
+		return err
+	}
+
+	c := Check{Secret: r, Client: w.client}
+	for _, p := range w.predicates {
+		err := p(ctx, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.persist && r.Data != "" {
+		err := w.client.WriteOutputs(r.Data)
+		if err != nil {
+			return errors.Join(err, errors.New("unable to persist secret"))
+		}
+	}
+
+	return nil
+}
+
+func (w *waiter) close() {
+	if w.owned {
+		_ = w.client.Close()
+	}
 }
@@ -6,6 +6,8 @@
 package startup
 
 import (
+	"context"
+	"fmt"
 	"github.com/spiffe/vsecm-sdk-go/internal/debug"
 	"os"
 	"time"
@@ -15,25 +17,50 @@ import (
 
 // Watch continuously polls the associated secret of the workload to exist.
 // If the secret exists, and it is not empty, the function exits the init
-// container with a success status code (0).
+// container with a success status code (0). If
+// VSECM_INIT_CONTAINER_PERSIST_SECRET is "true", the secret is written to the
+// outputs of the sidecar first.
+//
+// If the secret is not there before VSECM_INIT_CONTAINER_TIMEOUT elapses,
+// Watch prints the reason to the standard error, and exits the init
+// container with VSECM_INIT_CONTAINER_FAILURE_EXIT_CODE (1 by default); so
+// that the Pod shows Init:Error instead of hanging. There is no timeout if
+// the variable is not set.
 //
 //   - waitTimeBeforeExit: The duration to wait before a successful exit from
 //     the function.
 func Watch(waitTimeBeforeExit time.Duration) {
-	interval := env.PollIntervalForInitContainer()
-	ticker := time.NewTicker(interval)
+	err := WatchContext(context.Background(), waitTimeBeforeExit)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "VSecM init container:", err.Error())
+		os.Exit(env.FailureExitCodeForInitContainer())
+	}
+}
 
-	for {
-		select {
-		case <-ticker.C:
-			debug.Log("init:: tick")
-			if initialized() {
-				debug.Log("initialized... exiting the init process")
+// WatchContext is like Watch, but it returns an error instead of exiting
+// the process when the secret is not there in time, or when ctx is done,
+// including while it is waiting before the successful exit. See
+// WaitUntilReady.
+//
+// If VSECM_INIT_CONTAINER_HEALTH_BIND_ADDR is set (e.g. ":8087"),
+// WatchContext serves /healthz, /readyz, and /status on that address while
+// it polls.
+func WatchContext(ctx context.Context, waitTimeBeforeExit time.Duration) error {
+	err := WaitUntilReady(ctx, DefaultOptions())
+	if err != nil {
+		return err
+	}
 
-				time.Sleep(waitTimeBeforeExit)
+	debug.Log("initialized... exiting the init process")
 
-				os.Exit(0)
-			}
-		}
+	t := time.NewTimer(waitTimeBeforeExit)
+	select {
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	case <-t.C:
 	}
+
+	os.Exit(0)
+	return nil
 }